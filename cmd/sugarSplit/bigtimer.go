@@ -1,106 +1,287 @@
 package main
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
 
-var bigNumbers = [][]string{
-	{ // 0
-		"█▀█",
-		"█ █",
-		"▀▀▀",
-	},
-	{ // 1
-		"▄█ ",
-		" █ ",
-		"▀▀▀",
-	},
-	{ // 2
-		"▀▀█",
-		"█▀▀",
-		"▀▀▀",
+	"github.com/charmbracelet/lipgloss"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// bigFont is a set of glyphs for drawing the timer with block characters.
+// Every glyph of a font has the same number of rows.
+type bigFont struct {
+	// name is the font's name in the config
+	name   string
+	digits [10][]string
+	colon  []string
+	dot    []string
+	minus  []string
+	// gap is put between two neighbouring digits
+	gap string
+}
+
+var smallFont = bigFont{
+	name: sugarSplitCore.TimerFontSmall,
+	digits: [10][]string{
+		{ // 0
+			"█▀█",
+			"█ █",
+			"▀▀▀",
+		},
+		{ // 1
+			"▄█ ",
+			" █ ",
+			"▀▀▀",
+		},
+		{ // 2
+			"▀▀█",
+			"█▀▀",
+			"▀▀▀",
+		},
+		{ // 3
+			"▀▀█",
+			"▀▀█",
+			"▀▀▀",
+		},
+		{ // 4
+			"█ █",
+			"▀▀█",
+			"  ▀",
+		},
+		{ // 5
+			"█▀▀",
+			"▀▀█",
+			"▀▀▀",
+		},
+		{ // 6
+			"█▀▀",
+			"█▀█",
+			"▀▀▀",
+		},
+		{ // 7
+			"█▀█",
+			"  █",
+			"  ▀",
+		},
+		{ // 8
+			"█▀█",
+			"█▀█",
+			"▀▀▀",
+		},
+		{ // 9
+			"█▀█",
+			"▀▀█",
+			"  ▀",
+		},
 	},
-	{ // 3
-		"▀▀█",
-		"▀▀█",
-		"▀▀▀",
+	colon: []string{
+		" ▀ ",
+		"   ",
+		" ▀ ",
 	},
-	{ // 4
-		"█ █",
-		"▀▀█",
-		"  ▀",
+	dot: []string{
+		"   ",
+		"   ",
+		" ▀ ",
 	},
-	{ // 5
-		"█▀▀",
-		"▀▀█",
-		"▀▀▀",
+	minus: []string{
+		"   ",
+		"▀▀ ",
+		"   ",
 	},
-	{ // 6
-		"█▀▀",
-		"█▀█",
-		"▀▀▀",
+	gap: "  ",
+}
+
+var largeFont = bigFont{
+	name: sugarSplitCore.TimerFontLarge,
+	digits: [10][]string{
+		{ // 0
+			"█████",
+			"█   █",
+			"█   █",
+			"█   █",
+			"█████",
+		},
+		{ // 1
+			"  █  ",
+			" ██  ",
+			"  █  ",
+			"  █  ",
+			" ███ ",
+		},
+		{ // 2
+			"█████",
+			"    █",
+			"█████",
+			"█    ",
+			"█████",
+		},
+		{ // 3
+			"█████",
+			"    █",
+			" ████",
+			"    █",
+			"█████",
+		},
+		{ // 4
+			"█   █",
+			"█   █",
+			"█████",
+			"    █",
+			"    █",
+		},
+		{ // 5
+			"█████",
+			"█    ",
+			"█████",
+			"    █",
+			"█████",
+		},
+		{ // 6
+			"█████",
+			"█    ",
+			"█████",
+			"█   █",
+			"█████",
+		},
+		{ // 7
+			"█████",
+			"    █",
+			"   █ ",
+			"  █  ",
+			"  █  ",
+		},
+		{ // 8
+			"█████",
+			"█   █",
+			"█████",
+			"█   █",
+			"█████",
+		},
+		{ // 9
+			"█████",
+			"█   █",
+			"█████",
+			"    █",
+			"█████",
+		},
 	},
-	{ // 7
-		"█▀█",
-		"  █",
-		"  ▀",
+	colon: []string{
+		"   ",
+		" █ ",
+		"   ",
+		" █ ",
+		"   ",
 	},
-	{ // 8
-		"█▀█",
-		"█▀█",
-		"▀▀▀",
+	dot: []string{
+		"   ",
+		"   ",
+		"   ",
+		"   ",
+		" █ ",
 	},
-	{ // 9
-		"█▀█",
-		"▀▀█",
-		"  ▀",
+	minus: []string{
+		"    ",
+		"    ",
+		"████",
+		"    ",
+		"    ",
 	},
+	gap: " ",
 }
 
-func getBigNumber(n int) []string {
-	if n < 0 || n > 9 {
-		return []string{"   ", "   ", "   "}
+// timerFonts are the fonts picked from when the font is "auto", largest first
+var timerFonts = []*bigFont{&largeFont, &smallFont}
+
+// formatTimerText formats a duration the way the big timer shows it, with
+// the given number of decimal places. Time is truncated rather than rounded,
+// so the timer never shows a time that hasn't been reached yet.
+func formatTimerText(d time.Duration, accuracy int) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
 	}
-	return bigNumbers[n]
-}
 
-func getBigColon() []string {
-	return []string{
-		" ▀ ",
-		"   ",
-		" ▀ ",
+	h := d / time.Hour
+	m := d % time.Hour / time.Minute
+	s := d % time.Minute / time.Second
+
+	var text string
+	if h > 0 {
+		text = fmt.Sprintf("%s%d:%02d:%02d", sign, h, m, s)
+	} else {
+		text = fmt.Sprintf("%s%02d:%02d", sign, m, s)
 	}
-}
 
-func getBigDot() []string {
-	return []string{
-		"   ",
-		"   ",
-		" ▀ ",
+	if accuracy > 0 {
+		fraction := fmt.Sprintf("%09d", d%time.Second)
+		text += "." + fraction[:accuracy]
 	}
+	return text
 }
 
-func getBigTimer(d time.Duration) []string {
-	d = d.Round(time.Millisecond)
-	minutes := int(d.Minutes()) % 60
-	seconds := int(d.Seconds()) % 60
-	milliseconds := int(d.Milliseconds()) % 1000 / 10 // Get centiseconds (2 digits)
-
-	min1 := minutes / 10
-	min2 := minutes % 10
-	sec1 := seconds / 10
-	sec2 := seconds % 10
-	ms1 := milliseconds / 10
-	ms2 := milliseconds % 10
-
-	result := make([]string, 3)
-
-	// Combine each row
-	for i := 0; i < 3; i++ {
-		result[i] = getBigNumber(min1)[i] + "  " + getBigNumber(min2)[i] +
-			getBigColon()[i] +
-			getBigNumber(sec1)[i] + "  " + getBigNumber(sec2)[i] +
-			getBigDot()[i] +
-			getBigNumber(ms1)[i] + "  " + getBigNumber(ms2)[i]
+// render draws text made of digits, colons, dots and minus signs in the font
+func (f *bigFont) render(text string) []string {
+	rows := make([]strings.Builder, len(f.colon))
+
+	var prevDigit bool
+	for _, c := range text {
+		var glyph []string
+		isDigit := c >= '0' && c <= '9'
+		switch {
+		case isDigit:
+			glyph = f.digits[c-'0']
+		case c == ':':
+			glyph = f.colon
+		case c == '.':
+			glyph = f.dot
+		case c == '-':
+			glyph = f.minus
+		default:
+			continue
+		}
+
+		for i := range rows {
+			if isDigit && prevDigit {
+				rows[i].WriteString(f.gap)
+			}
+			rows[i].WriteString(glyph[i])
+		}
+		prevDigit = isDigit
 	}
 
+	result := make([]string, len(rows))
+	for i := range rows {
+		result[i] = rows[i].String()
+	}
 	return result
 }
+
+// getBigTimer draws a duration with the configured font and accuracy. With
+// the "auto" font the largest font that fits in width is used, falling back
+// to plain text on very narrow terminals.
+func getBigTimer(d time.Duration, config sugarSplitCore.TimerConfig, width int) []string {
+	accuracy := min(max(config.Accuracy, 0), 3)
+	text := formatTimerText(d, accuracy)
+
+	for _, font := range timerFonts {
+		if font.name == config.Font {
+			return font.render(text)
+		}
+	}
+	if config.Font == sugarSplitCore.TimerFontText {
+		return []string{text}
+	}
+
+	for _, font := range timerFonts {
+		lines := font.render(text)
+		if lipgloss.Width(lines[0]) <= width {
+			return lines
+		}
+	}
+	return []string{text}
+}
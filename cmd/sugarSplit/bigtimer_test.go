@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+func TestBigTimerFont(t *testing.T) {
+	d := 83*time.Second + 450*time.Millisecond
+	text := formatTimerText(d, 2)
+	largeWidth := lipgloss.Width(largeFont.render(text)[0])
+	smallWidth := lipgloss.Width(smallFont.render(text)[0])
+
+	tests := []struct {
+		name  string
+		font  string
+		width int
+		rows  int
+	}{
+		{"auto picks the largest font", sugarSplitCore.TimerFontAuto, largeWidth, len(largeFont.digits[0])},
+		{"auto falls back to the small font", sugarSplitCore.TimerFontAuto, largeWidth - 1, len(smallFont.digits[0])},
+		{"auto falls back to text", sugarSplitCore.TimerFontAuto, smallWidth - 1, 1},
+		{"small font on a wide terminal", sugarSplitCore.TimerFontSmall, 200, len(smallFont.digits[0])},
+		{"large font on a narrow terminal", sugarSplitCore.TimerFontLarge, 10, len(largeFont.digits[0])},
+		{"text", sugarSplitCore.TimerFontText, 200, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := getBigTimer(d, sugarSplitCore.TimerConfig{Accuracy: 2, Font: tt.font}, tt.width)
+			if len(lines) != tt.rows {
+				t.Errorf("got %d rows, want %d", len(lines), tt.rows)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+// cellPixelSize returns the size of a terminal cell in pixels, or false if
+// the terminal doesn't report its pixel size
+func cellPixelSize() (int, int, bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// runResetsCommand prints the reset analysis of a splits file as CSV
+func runResetsCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: sugarSplit resets <filename.lss>")
+	}
+
+	state, err := sugarSplitCore.LoadRun(args[0])
+	if err != nil {
+		return err
+	}
+
+	return sugarSplitCore.WriteResetCSV(os.Stdout, state.ResetAnalysis())
+}
+
+// runLayoutCommand converts a LiveSplit layout to the [ui] settings of
+// config.toml and prints them. Components that can't be converted are
+// listed on stderr.
+func runLayoutCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: sugarSplit layout <filename.lsl>")
+	}
+
+	config, warnings, err := sugarSplitCore.LoadLayout(args[0], sugarSplitCore.DefaultUIConfig())
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err != nil {
+		return err
+	}
+
+	return toml.NewEncoder(os.Stdout).Encode(struct {
+		UI *sugarSplitCore.UIConfig `toml:"ui"`
+	}{config})
+}
+
+// setSplitsFromList replaces the splits of a new splits file with the ones
+// in a split list file
+func setSplitsFromList(state *sugarSplitCore.LiveSplitState, path string) error {
+	entries, err := sugarSplitCore.LoadSplitList(path)
+	if err != nil {
+		return err
+	}
+	return state.SetSegmentsFromList(entries)
+}
+
+// runConvertCommand converts splits between LiveSplit's .lss format and the
+// splits.io Exchange Format (.json), picked by the file extensions
+func runConvertCommand(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: sugarSplit convert <input.lss|input.json> <output.lss|output.json>")
+	}
+
+	var state *sugarSplitCore.LiveSplitState
+	if isSplitsIOFile(args[0]) {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		if state, err = sugarSplitCore.ImportSplitsIO(data); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if state, err = sugarSplitCore.LoadRun(args[0]); err != nil {
+			return err
+		}
+	}
+
+	if !isSplitsIOFile(args[1]) {
+		return sugarSplitCore.SaveRun(state, args[1])
+	}
+	data, err := sugarSplitCore.ExportSplitsIO(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(args[1], data, 0644)
+}
+
+// isSplitsIOFile reports whether a path is a splits.io Exchange Format file
+func isSplitsIOFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// runImportCommand converts splits of another timer (Urn, Llanfair, WSplit,
+// Time Split Tracker or splits.io) to a LiveSplit splits file. Without an
+// output path it's saved next to the input with a .lss extension.
+func runImportCommand(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: sugarSplit import <file> [output.lss]")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	state, format, err := sugarSplitCore.ImportRun(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", args[0], err)
+	}
+
+	output := strings.TrimSuffix(args[0], filepath.Ext(args[0])) + ".lss"
+	if len(args) == 2 {
+		output = args[1]
+	} else if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("%s already exists, give an output path to overwrite it", output)
+	}
+
+	if err := sugarSplitCore.SaveRun(state, output); err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d splits from %s to %s\n", len(state.Segments.Segments), format, output)
+	return nil
+}
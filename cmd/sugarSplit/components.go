@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// contentWidth is the width left inside the padding of a line
+func (m model) contentWidth() int {
+	return max(m.width, 40) - 2
+}
+
+// alignLine places a line in the content width the way the component is aligned
+func (m model) alignLine(line, align string) string {
+	position := lipgloss.Left
+	switch align {
+	case sugarSplitCore.AlignCenter:
+		position = lipgloss.Center
+	case sugarSplitCore.AlignRight:
+		position = lipgloss.Right
+	}
+	return lipgloss.PlaceHorizontal(m.contentWidth(), position, line)
+}
+
+// renderTitle shows the game and category with the number of finished and
+// total attempts on the right
+func (m model) renderTitle(styles Styles, run sugarSplitCore.RunSnapshot) string {
+	var s strings.Builder
+
+	counter := fmt.Sprintf("%d/%d", m.finishedAttempts, run.State.AttemptCount)
+	side := lipgloss.Width(counter)
+	middle := max(m.contentWidth()-side*2, 1)
+
+	category := lipgloss.PlaceHorizontal(middle, lipgloss.Center, truncate(run.State.CategoryName, middle))
+	line := strings.Repeat(" ", side) + styles.title.UnsetWidth().Render(category) + styles.muted.Render(counter)
+
+	s.WriteString(styles.title.Render(truncate(run.State.GameName, m.contentWidth())))
+	s.WriteString("\n")
+	s.WriteString(styles.segment.Render(line))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// renderClock shows the time of day
+func (m model) renderClock(styles Styles, config sugarSplitCore.UIComponentConfig) string {
+	format := config.Format
+	if format == "" {
+		format = sugarSplitCore.DefaultClockFormat
+	}
+	line := m.alignLine(time.Now().Format(format), config.Align)
+	return styles.segment.Render(line) + "\n"
+}
+
+// renderSessionTimer shows how long sugarSplit has been open
+func (m model) renderSessionTimer(styles Styles, config sugarSplitCore.UIComponentConfig) string {
+	session := time.Since(m.sessionStart).Truncate(time.Second)
+	line := m.alignLine("Session: "+formatTimerText(session, 0), config.Align)
+	return styles.segment.Render(line) + "\n"
+}
+
+// renderText shows the configured text with its {variables} filled in
+func (m model) renderText(styles Styles, run sugarSplitCore.RunSnapshot, config sugarSplitCore.UIComponentConfig) string {
+	var s strings.Builder
+
+	text := m.textVariables(run).Replace(config.Text)
+	for _, line := range strings.Split(text, "\n") {
+		s.WriteString(styles.segment.Render(m.alignLine(truncate(line, m.contentWidth()), config.Align)))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// textVariables returns the replacer for the variables of the text component
+func (m model) textVariables(run sugarSplitCore.RunSnapshot) *strings.Replacer {
+	segments := run.State.Segments.Segments
+
+	split := "-"
+	if current := max(run.CurrentSplit, 0); current < len(segments) {
+		split = segments[current].Name
+	}
+	duration := func(d time.Duration) string {
+		if d <= 0 {
+			return "-"
+		}
+		return sugarSplitCore.FormatDuration(d)
+	}
+
+	return strings.NewReplacer(
+		"{game}", run.State.GameName,
+		"{category}", run.State.CategoryName,
+		"{platform}", run.State.Metadata.Platform.Name,
+		"{attempts}", strconv.Itoa(run.State.AttemptCount),
+		"{finished}", strconv.Itoa(m.finishedAttempts),
+		"{comparison}", run.ComparisonName,
+		"{split}", split,
+		"{split_number}", strconv.Itoa(min(max(run.CurrentSplit, 0)+1, len(segments))),
+		"{splits}", strconv.Itoa(len(segments)),
+		"{time}", sugarSplitCore.FormatDuration(run.CurrentTime),
+		"{pb}", duration(sugarSplitCore.ComparisonSplitTime(segments, len(segments)-1, sugarSplitCore.ComparisonPersonalBest)),
+		"{sum_of_best}", duration(sugarSplitCore.GetSumOfBest(segments)),
+		"{clock}", time.Now().Format(sugarSplitCore.DefaultClockFormat),
+		"{session}", formatTimerText(time.Since(m.sessionStart), 0),
+	)
+}
+
+// renderSeparator draws a horizontal line
+func (m model) renderSeparator(styles Styles) string {
+	return styles.segment.Render(styles.muted.Render(strings.Repeat("─", m.contentWidth()))) + "\n"
+}
+
+// renderSumOfBest shows the sum of the golds
+func (m model) renderSumOfBest(styles Styles, run sugarSplitCore.RunSnapshot) string {
+	sumOfBest := "-"
+	if d := sugarSplitCore.GetSumOfBest(run.State.Segments.Segments); d > 0 {
+		sumOfBest = sugarSplitCore.FormatDuration(d)
+	}
+	return styles.segment.Render("Sum of Best: "+sumOfBest) + "\n"
+}
+
+// renderPossibleTimeSave shows how much faster the current segment could be
+// than the comparison
+func (m model) renderPossibleTimeSave(styles Styles, run sugarSplitCore.RunSnapshot) string {
+	timeSave := "-"
+	column := sugarSplitCore.SplitColumnConfig{Type: sugarSplitCore.ColumnPossibleTimeSave}
+	if value := run.SplitColumn(column, max(run.CurrentSplit, 0)); value.Valid {
+		timeSave = sugarSplitCore.FormatDuration(value.Time)
+	}
+	return styles.segment.Render("Possible Time Save: "+timeSave) + "\n"
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// Kinds of dots on the graph, later kinds win when they share a cell
+const (
+	dotNone = iota
+	dotAxis
+	dotAhead
+	dotBehind
+	dotGold
+)
+
+// brailleBits maps a dot position within a braille cell to its bit
+var brailleBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// brailleCanvas draws on a grid of 2x4 dots per terminal cell
+type brailleCanvas struct {
+	width, height int
+	cells         [][]rune
+	kinds         [][]int
+}
+
+func newBrailleCanvas(width, height int) *brailleCanvas {
+	c := &brailleCanvas{width: width, height: height}
+	c.cells = make([][]rune, height)
+	c.kinds = make([][]int, height)
+	for i := range c.cells {
+		c.cells[i] = make([]rune, width)
+		c.kinds[i] = make([]int, width)
+	}
+	return c
+}
+
+// set turns on the dot at x, y in dot coordinates
+func (c *brailleCanvas) set(x, y, kind int) {
+	if x < 0 || y < 0 || x >= c.width*2 || y >= c.height*4 {
+		return
+	}
+
+	row, col := y/4, x/2
+	c.cells[row][col] |= brailleBits[x%2][y%4]
+	if kind > c.kinds[row][col] {
+		c.kinds[row][col] = kind
+	}
+}
+
+// line draws a straight line between two dots
+func (c *brailleCanvas) line(x0, y0, x1, y1, kind int) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		c.set(x0, y0, kind)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// render returns the canvas as lines, coloring each cell by its dot kind
+func (c *brailleCanvas) render(styles map[int]lipgloss.Style) []string {
+	lines := make([]string, c.height)
+	for row := range c.cells {
+		var line strings.Builder
+		for col, bits := range c.cells[row] {
+			char := " "
+			if bits != 0 {
+				char = string(0x2800 + bits)
+			}
+			if style, ok := styles[c.kinds[row][col]]; ok && bits != 0 {
+				char = style.Render(char)
+			}
+			line.WriteString(char)
+		}
+		lines[row] = line.String()
+	}
+	return lines
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// renderGraph draws the delta to the comparison at every completed split,
+// with time running left to right and time lost going up
+func (m model) renderGraph(styles Styles, run sugarSplitCore.RunSnapshot) string {
+	var s strings.Builder
+
+	width := m.width - 2
+	if width < 10 {
+		width = 10
+	}
+	height := m.run.UIConfig.Graph.Height
+	if height <= 0 {
+		height = sugarSplitCore.DefaultGraphHeight
+	}
+
+	type point struct {
+		time, delta time.Duration
+		gold        bool
+	}
+	points := []point{{}}
+	minDelta, maxDelta, maxTime := time.Duration(0), time.Duration(0), time.Duration(0)
+	for i := 0; i < run.CurrentSplit && i < len(run.Splits); i++ {
+		if run.Splits[i] == 0 || run.ComparisonTime(i) == 0 {
+			continue
+		}
+		p := point{time: run.Splits[i], delta: run.Comparison[i], gold: run.IsGold[i]}
+		points = append(points, p)
+		minDelta = min(minDelta, p.delta)
+		maxDelta = max(maxDelta, p.delta)
+		maxTime = max(maxTime, p.time)
+	}
+	if maxTime == 0 {
+		maxTime = time.Second
+	}
+	if minDelta == maxDelta {
+		minDelta, maxDelta = -time.Second, time.Second
+	}
+
+	canvas := newBrailleCanvas(width, height)
+	dotsX, dotsY := width*2-1, height*4-1
+	toX := func(t time.Duration) int {
+		return int(float64(t) / float64(maxTime) * float64(dotsX))
+	}
+	toY := func(d time.Duration) int {
+		return int(float64(maxDelta-d)/float64(maxDelta-minDelta)*float64(dotsY) + 0.5)
+	}
+
+	// Zero line
+	zero := toY(0)
+	for x := 0; x <= dotsX; x += 2 {
+		canvas.set(x, zero, dotAxis)
+	}
+
+	for i := 1; i < len(points); i++ {
+		prev, p := points[i-1], points[i]
+		kind := dotAhead
+		if p.delta > 0 {
+			kind = dotBehind
+		}
+		canvas.line(toX(prev.time), toY(prev.delta), toX(p.time), toY(p.delta), kind)
+		if p.gold {
+			x, y := toX(p.time), toY(p.delta)
+			canvas.set(x, y, dotGold)
+			canvas.set(x-1, y, dotGold)
+			canvas.set(x, y-1, dotGold)
+			canvas.set(x-1, y-1, dotGold)
+		}
+	}
+
+	lines := canvas.render(map[int]lipgloss.Style{
+		dotAxis:   styles.muted,
+		dotAhead:  styles.ahead,
+		dotBehind: styles.behind,
+		dotGold:   styles.gold,
+	})
+	for _, line := range lines {
+		s.WriteString(styles.segment.Render(line))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
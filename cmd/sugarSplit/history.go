@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// enterHistoryMode switches to the run history with the newest attempt selected
+func (m model) enterHistoryMode() model {
+	summaries := m.run.State().AttemptSummaries()
+
+	// Newest attempts first
+	m.history = make([]sugarSplitCore.AttemptSummary, len(summaries))
+	for i, summary := range summaries {
+		m.history[len(summaries)-1-i] = summary
+	}
+
+	m.mode = modeHistory
+	m.historyIndex = 0
+	m.historyDetail = false
+	return m
+}
+
+func (m model) updateHistoryMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "h":
+			if m.historyDetail {
+				m.historyDetail = false
+			} else {
+				m.history = nil
+				m.mode = modeNormal
+			}
+		case "enter":
+			if len(m.history) > 0 {
+				m.historyDetail = true
+				m.historyScroll = 0
+			}
+		case "up", "k":
+			if m.historyDetail {
+				if m.historyScroll > 0 {
+					m.historyScroll--
+				}
+			} else if m.historyIndex > 0 {
+				m.historyIndex--
+			}
+		case "down", "j":
+			if m.historyDetail {
+				if m.historyScroll < m.history[m.historyIndex].Reached-1 {
+					m.historyScroll++
+				}
+			} else if m.historyIndex < len(m.history)-1 {
+				m.historyIndex++
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m model) renderHistoryMode() string {
+	var s strings.Builder
+	styles := m.styles()
+	state := m.run.State()
+
+	s.WriteString("\n")
+	s.WriteString(styles.title.Render("Run History"))
+	s.WriteString("\n")
+	s.WriteString(styles.title.Render(state.GameName + " - " + state.CategoryName))
+	s.WriteString("\n\n")
+
+	var body string
+	if m.historyDetail {
+		body = m.renderAttemptDetail(styles, state, m.history[m.historyIndex])
+	} else {
+		body = m.renderAttemptList(styles, state)
+	}
+	s.WriteString(body)
+
+	// Calculate padding to push controls to bottom
+	contentHeight := 4 + strings.Count(body, "\n") + 2
+	if m.height > contentHeight {
+		s.WriteString(strings.Repeat("\n", m.height-contentHeight))
+	}
+
+	s.WriteString("\n")
+	if m.historyDetail {
+		s.WriteString(styles.controls.Render("j/k: Scroll | Esc: Back"))
+	} else {
+		s.WriteString(styles.controls.Render("j/k: Navigate | Enter: Details | Esc: Back"))
+	}
+
+	return s.String()
+}
+
+func (m model) renderAttemptList(styles Styles, state *sugarSplitCore.LiveSplitState) string {
+	var s strings.Builder
+
+	if len(m.history) == 0 {
+		s.WriteString(styles.segment.Render("No attempts yet"))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	finished := 0
+	for _, attempt := range m.history {
+		if attempt.Finished {
+			finished++
+		}
+	}
+	summary := fmt.Sprintf("%d attempts, %d finished", len(m.history), finished)
+	if prediction := sugarSplitCore.NewPredictor(state, predictionSeed).Predict(0, 0, 0); prediction.Samples > 0 {
+		summary += fmt.Sprintf(", %.1f%% PB chance from the start", prediction.PBChance*100)
+	}
+	s.WriteString(styles.segment.Render(summary))
+	s.WriteString("\n\n")
+
+	nameWidth := m.width - 46
+	if nameWidth < 8 {
+		nameWidth = 8
+	}
+
+	start, end := listWindow(len(m.history), m.historyIndex, m.height-10)
+	for i := start; i < end; i++ {
+		attempt := m.history[i]
+
+		date := "-"
+		if !attempt.Started.IsZero() {
+			date = attempt.Started.Local().Format("2006-01-02 15:04")
+		}
+
+		duration := "-"
+		if attempt.Duration > 0 {
+			duration = sugarSplitCore.FormatDuration(attempt.Duration)
+		}
+
+		furthest := "-"
+		if attempt.Finished {
+			furthest = "Finished"
+		} else if attempt.Reached > 0 {
+			furthest = state.Segments.Segments[attempt.Reached-1].Name
+		}
+
+		pb := "  "
+		if attempt.PB {
+			pb = "PB"
+		}
+
+		line := fmt.Sprintf("#%-5s %-16s %12s  %s %s", attempt.ID, date, duration, fitWidth(furthest, nameWidth), pb)
+		if i == m.historyIndex {
+			s.WriteString(styles.currentSegment.Render(line))
+		} else if attempt.PB {
+			s.WriteString(styles.segment.Render(styles.gold.Render(line)))
+		} else {
+			s.WriteString(styles.segment.Render(line))
+		}
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+func (m model) renderAttemptDetail(styles Styles, state *sugarSplitCore.LiveSplitState, attempt sugarSplitCore.AttemptSummary) string {
+	var s strings.Builder
+
+	header := fmt.Sprintf("Attempt #%s", attempt.ID)
+	if !attempt.Started.IsZero() {
+		header += " - " + attempt.Started.Local().Format("2006-01-02 15:04")
+	}
+	if attempt.Duration > 0 {
+		header += " - " + sugarSplitCore.FormatDuration(attempt.Duration)
+	}
+	if attempt.PB {
+		header += " (PB)"
+	}
+	s.WriteString(styles.segment.Render(header))
+	s.WriteString("\n\n")
+
+	splits := state.AttemptSplits(attempt.ID)
+	nameWidth := m.width - 32
+	if nameWidth < 8 {
+		nameWidth = 8
+	}
+
+	lines := m.height - 12
+	if lines < 1 {
+		lines = 1
+	}
+	end := m.historyScroll + lines
+	if end > len(splits) {
+		end = len(splits)
+	}
+
+	for _, split := range splits[min(m.historyScroll, end):end] {
+		segmentTime, splitTime := "-", "-"
+		if !split.Skipped {
+			segmentTime = sugarSplitCore.FormatDuration(split.SegmentTime)
+			splitTime = sugarSplitCore.FormatDuration(split.SplitTime)
+		}
+		s.WriteString(styles.segment.Render(fmt.Sprintf("%s %14s %14s", fitWidth(split.Name, nameWidth), segmentTime, splitTime)))
+		s.WriteString("\n")
+	}
+
+	if !attempt.Finished {
+		s.WriteString("\n")
+		if len(splits) < len(state.Segments.Segments) {
+			s.WriteString(styles.segment.Render(styles.behind.Render("Reset during " + state.Segments.Segments[len(splits)].Name)))
+		} else {
+			s.WriteString(styles.segment.Render(styles.behind.Render("Reset")))
+		}
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
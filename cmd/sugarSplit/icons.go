@@ -0,0 +1,452 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// iconWidth is the number of cells an icon takes
+const iconWidth = 2
+
+// Cell size assumed when the terminal doesn't report its pixel size
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+// kittyPlaceholder is drawn where a kitty image goes, the diacritics after
+// it give the row and column of the image that is shown in the cell
+const kittyPlaceholder = '\U0010EEEE'
+
+var kittyDiacritics = []rune{0x0305, 0x030D, 0x030E, 0x0310}
+
+// With sixel scrolling turned off (DECSET 8452) the cursor stays right of a
+// sixel image instead of moving below it
+const (
+	sixelCursorRight = "\x1b[?8452h"
+	sixelCursorBelow = "\x1b[?8452l"
+)
+
+// iconSet draws the icons of a splits file with a terminal graphics protocol
+type iconSet struct {
+	// cells maps icon data from the splits file to the text that draws it
+	cells map[string]string
+	// setup is sent to the terminal before the interface starts, teardown
+	// after it quits
+	setup    string
+	teardown string
+	// sixels are the cells drawn with sixel graphics
+	sixels []string
+}
+
+// newIconSet prepares every icon of the splits. Icons that can't be decoded
+// and terminals without graphics support get no icons.
+func newIconSet(state *sugarSplitCore.LiveSplitState, setting string) *iconSet {
+	set := &iconSet{cells: map[string]string{}}
+
+	protocol := setting
+	if protocol == sugarSplitCore.IconsAuto {
+		protocol = detectIconProtocol()
+	}
+	if protocol != sugarSplitCore.IconsKitty && protocol != sugarSplitCore.IconsSixel {
+		return set
+	}
+
+	cellWidth, cellHeight, ok := cellPixelSize()
+	if !ok {
+		cellWidth, cellHeight = defaultCellWidth, defaultCellHeight
+	}
+
+	icons := []string{state.GameIcon}
+	for _, segment := range state.Segments.Segments {
+		icons = append(icons, segment.Icon)
+	}
+
+	var setup strings.Builder
+	for _, icon := range icons {
+		if _, done := set.cells[icon]; done || icon == "" {
+			continue
+		}
+		img, err := sugarSplitCore.DecodeIcon(icon)
+		if err != nil {
+			continue
+		}
+
+		if protocol == sugarSplitCore.IconsKitty {
+			id := kittyImageID(icon)
+			setup.WriteString(kittyTransmit(id, img))
+			set.cells[icon] = kittyPlaceholders(id)
+		} else {
+			// Sixel images are drawn in bands of 6 pixels
+			height := max(cellHeight/6*6, 6)
+			sixel := encodeSixel(fitImage(img, cellWidth*iconWidth, height))
+			// Draw over blank cells and leave the cursor right of the image,
+			// so the line is as wide as it is measured
+			set.cells[icon] = fmt.Sprintf("%s\x1b[%dD%s", strings.Repeat(" ", iconWidth), iconWidth, sixel)
+			set.sixels = append(set.sixels, set.cells[icon])
+		}
+	}
+
+	switch {
+	case setup.Len() > 0:
+		// Kitty keeps separate images for the alternate screen, so it's
+		// entered first
+		set.setup = "\x1b[?1049h" + setup.String()
+	case len(set.sixels) > 0:
+		set.setup, set.teardown = sixelCursorRight, sixelCursorBelow
+	}
+
+	return set
+}
+
+// icon returns the text that draws an icon, or false if there is none
+func (s *iconSet) icon(data string) (string, bool) {
+	cell, ok := s.cells[data]
+	return cell, ok
+}
+
+// hasSegmentIcons reports whether any of the segments has an icon to draw
+func (s *iconSet) hasSegmentIcons(segments []sugarSplitCore.Segment) bool {
+	for _, segment := range segments {
+		if _, ok := s.cells[segment.Icon]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sendSetup transmits the icons to the terminal
+func (s *iconSet) sendSetup() {
+	os.Stdout.WriteString(s.setup)
+}
+
+// sendTeardown restores the terminal modes changed by sendSetup
+func (s *iconSet) sendTeardown() {
+	os.Stdout.WriteString(s.teardown)
+}
+
+// output returns where the interface is drawn. Sixel images are only sent
+// again when they have been drawn over.
+func (s *iconSet) output(f *os.File) io.Writer {
+	if len(s.sixels) == 0 {
+		return f
+	}
+	return &sixelOutput{File: f, cells: s.sixels}
+}
+
+// sixelOutput writes the frames of the interface to the terminal, moving the
+// cursor over sixel images that are still on screen instead of sending them
+// again. The renderer rewrites whole lines, and text drawn over an image
+// erases it, so an image is only left out where the line before had the
+// same one in the same column.
+type sixelOutput struct {
+	*os.File
+	cells []string
+	// drawn holds the images on every line of the screen
+	drawn map[int][]sixelPlacement
+}
+
+// sixelPlacement is an image drawn at a column of a line
+type sixelPlacement struct {
+	column int
+	cell   int
+}
+
+func (o *sixelOutput) Write(p []byte) (int, error) {
+	frame := string(p)
+	if !strings.HasPrefix(frame, ansi.HomeCursorPosition) {
+		// Clearing the screen removes the images
+		if strings.Contains(frame, ansi.EraseEntireScreen) {
+			o.drawn = nil
+		}
+		return o.File.Write(p)
+	}
+
+	if _, err := o.File.WriteString(o.skipDrawn(frame)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// skipDrawn replaces the images of a frame that are already on screen.
+// Frames start at the top left, unchanged lines are skipped by moving the
+// cursor down and written ones end with a line break.
+func (o *sixelOutput) skipDrawn(frame string) string {
+	// A frame skipping no lines is a repaint, e.g. after a resize, and the
+	// images may be gone
+	if o.drawn == nil || !strings.Contains(frame, ansi.CursorDown1) {
+		o.drawn = map[int][]sixelPlacement{}
+	}
+
+	var out strings.Builder
+	out.WriteString(ansi.HomeCursorPosition)
+	lines := strings.Split(strings.TrimPrefix(frame, ansi.HomeCursorPosition), "\r\n")
+	row := 0
+	for i, line := range lines {
+		for strings.HasPrefix(line, ansi.CursorDown1) {
+			out.WriteString(ansi.CursorDown1)
+			line = line[len(ansi.CursorDown1):]
+			row++
+		}
+		out.WriteString(o.skipDrawnLine(row, line))
+		if i < len(lines)-1 {
+			out.WriteString("\r\n")
+			row++
+		}
+	}
+	return out.String()
+}
+
+// skipDrawnLine replaces the images of a written line that were drawn at the
+// same place before, and remembers the images of the line
+func (o *sixelOutput) skipDrawnLine(row int, line string) string {
+	previous := o.drawn[row]
+	var placed []sixelPlacement
+	var out strings.Builder
+
+	column := 0
+	for {
+		start, cell := -1, -1
+		for c, text := range o.cells {
+			if i := strings.Index(line, text); i >= 0 && (start < 0 || i < start) {
+				start, cell = i, c
+			}
+		}
+		if start < 0 {
+			break
+		}
+
+		column += ansi.StringWidth(line[:start])
+		placement := sixelPlacement{column: column, cell: cell}
+		placed = append(placed, placement)
+
+		out.WriteString(line[:start])
+		if slices.Contains(previous, placement) {
+			out.WriteString(ansi.CursorRight(iconWidth))
+		} else {
+			out.WriteString(o.cells[cell])
+		}
+		line = line[start+len(o.cells[cell]):]
+		column += iconWidth
+	}
+	out.WriteString(line)
+
+	if placed == nil {
+		delete(o.drawn, row)
+	} else {
+		o.drawn[row] = placed
+	}
+	return out.String()
+}
+
+// detectIconProtocol guesses the graphics protocol of the terminal from its
+// environment. Terminal multiplexers don't pass images through.
+func detectIconProtocol() string {
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return sugarSplitCore.IconsOff
+	case os.Getenv("KITTY_WINDOW_ID") != "" || strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") || program == "ghostty":
+		return sugarSplitCore.IconsKitty
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.Contains(term, "contour") ||
+		program == "WezTerm" || program == "iTerm.app" || os.Getenv("KONSOLE_VERSION") != "":
+		return sugarSplitCore.IconsSixel
+	}
+	return sugarSplitCore.IconsOff
+}
+
+// kittyImageID derives a stable image ID from the icon data. IDs are kept to
+// 24 bits so they fit in the placeholder's foreground color.
+func kittyImageID(data string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(data))
+	if id := h.Sum32() & 0xffffff; id != 0 {
+		return id
+	}
+	return 1
+}
+
+// kittyTransmit sends an image to the terminal with a virtual placement, so
+// it shows wherever its placeholders are drawn
+func kittyTransmit(id uint32, img image.Image) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	// The data is sent in chunks of at most 4096 bytes
+	var s strings.Builder
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(len(data), 4096)]
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+
+		if first {
+			fmt.Fprintf(&s, "\x1b_Ga=T,U=1,f=100,t=d,i=%d,c=%d,r=1,q=2,m=%d;%s\x1b\\", id, iconWidth, more, chunk)
+		} else {
+			fmt.Fprintf(&s, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return s.String()
+}
+
+// kittyPlaceholders draws the cells of an image, the foreground color
+// carries the image ID
+func kittyPlaceholders(id uint32) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "\x1b[38;2;%d;%d;%dm", id>>16&0xff, id>>8&0xff, id&0xff)
+	for col := 0; col < iconWidth; col++ {
+		s.WriteRune(kittyPlaceholder)
+		s.WriteRune(kittyDiacritics[0])
+		s.WriteRune(kittyDiacritics[col])
+	}
+	s.WriteString("\x1b[39m")
+	return s.String()
+}
+
+// fitImage scales an image to fit in width by height pixels keeping its
+// aspect ratio, centered on a transparent background. Every pixel is the
+// average of the pixels it covers.
+func fitImage(img image.Image, width, height int) *image.NRGBA {
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	b := img.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return out
+	}
+
+	scale := min(float64(width)/float64(b.Dx()), float64(height)/float64(b.Dy()))
+	w, h := max(int(float64(b.Dx())*scale), 1), max(int(float64(b.Dy())*scale), 1)
+	offsetX, offsetY := (width-w)/2, (height-h)/2
+
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+max((y+1)*b.Dy()/h, y*b.Dy()/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+max((x+1)*b.Dx()/w, x*b.Dx()/w+1)
+
+			// Sum premultiplied colors so transparent pixels don't darken
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			out.Set(offsetX+x, offsetY+y, color.NRGBA{
+				R: uint8(r * 0xff / a),
+				G: uint8(g * 0xff / a),
+				B: uint8(bl * 0xff / a),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return out
+}
+
+// encodeSixel encodes an image as sixel graphics with a 6x6x6 color cube.
+// Mostly transparent pixels are left out so the background shows through.
+func encodeSixel(img *image.NRGBA) string {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	// Palette index of every pixel, -1 for transparent ones
+	pixels := make([]int, width*height)
+	used := map[int]bool{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := img.NRGBAAt(b.Min.X+x, b.Min.Y+y)
+			index := -1
+			if c.A >= 0x80 {
+				index = int(c.R)*5/255*36 + int(c.G)*5/255*6 + int(c.B)*5/255
+				used[index] = true
+			}
+			pixels[y*width+x] = index
+		}
+	}
+
+	colors := make([]int, 0, len(used))
+	for index := range used {
+		colors = append(colors, index)
+	}
+	sort.Ints(colors)
+
+	var s strings.Builder
+	// Transparent background, 1:1 pixel aspect ratio
+	fmt.Fprintf(&s, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for _, index := range colors {
+		fmt.Fprintf(&s, "#%d;2;%d;%d;%d", index, index/36*20, index/6%6*20, index%6*20)
+	}
+
+	for band := 0; band < height; band += 6 {
+		first := true
+		for _, index := range colors {
+			row := make([]byte, width)
+			found := false
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = 63 + bits
+				found = found || bits != 0
+			}
+			if !found {
+				continue
+			}
+
+			if !first {
+				s.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&s, "#%d", index)
+			writeSixelRow(&s, row)
+		}
+		if band+6 < height {
+			s.WriteByte('-')
+		}
+	}
+
+	s.WriteString("\x1b\\")
+	return s.String()
+}
+
+// writeSixelRow writes sixel characters with runs of the same one compressed
+func writeSixelRow(s *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		run := 1
+		for i+run < len(row) && row[i+run] == row[i] {
+			run++
+		}
+		if run > 3 {
+			fmt.Fprintf(s, "!%d%c", run, row[i])
+		} else {
+			s.WriteString(strings.Repeat(string(row[i]), run))
+		}
+		i += run
+	}
+}
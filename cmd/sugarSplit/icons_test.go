@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEncodeSixel(t *testing.T) {
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	green := color.NRGBA{G: 0xff, A: 0xff}
+	blue := color.NRGBA{B: 0xff, A: 0xff}
+
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		pixel  func(x, y int) color.NRGBA
+		want   string
+	}{
+		{
+			name: "transparent column", width: 2, height: 6,
+			pixel: func(x, y int) color.NRGBA {
+				if x == 0 {
+					return red
+				}
+				return color.NRGBA{R: 0xff, A: 0x7f}
+			},
+			want: "\x1bP0;1;0q\"1;1;2;6#180;2;100;0;0#180~?\x1b\\",
+		},
+		{
+			name: "repeated pixels", width: 5, height: 1,
+			pixel: func(x, y int) color.NRGBA { return blue },
+			want:  "\x1bP0;1;0q\"1;1;5;1#5;2;0;0;100#5!5@\x1b\\",
+		},
+		{
+			name: "two colors in a band", width: 1, height: 6,
+			pixel: func(x, y int) color.NRGBA {
+				if y < 3 {
+					return red
+				}
+				return green
+			},
+			want: "\x1bP0;1;0q\"1;1;1;6#30;2;0;100;0#180;2;100;0;0#30w$#180F\x1b\\",
+		},
+		{
+			name: "two bands", width: 1, height: 12,
+			pixel: func(x, y int) color.NRGBA {
+				if y < 6 {
+					return red
+				}
+				return green
+			},
+			want: "\x1bP0;1;0q\"1;1;1;12#30;2;0;100;0#180;2;100;0;0#180~-#30~\x1b\\",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewNRGBA(image.Rect(0, 0, tt.width, tt.height))
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					img.SetNRGBA(x, y, tt.pixel(x, y))
+				}
+			}
+			if got := encodeSixel(img); got != tt.want {
+				t.Errorf("encodeSixel = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitImage(t *testing.T) {
+	// A wide image is scaled down to the full width and centered vertically
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{B: 0xff, A: 0xff})
+		}
+	}
+
+	out := fitImage(img, 4, 4)
+	for y := 0; y < 4; y++ {
+		want := uint8(0)
+		if y == 1 || y == 2 {
+			want = 0xff
+		}
+		for x := 0; x < 4; x++ {
+			if c := out.NRGBAAt(x, y); c.A != want || (want != 0 && c.B != 0xff) {
+				t.Errorf("pixel %d,%d = %v, want alpha %d", x, y, c, want)
+			}
+		}
+	}
+}
+
+func TestKittyTransmit(t *testing.T) {
+	// Noise doesn't compress, so the PNG takes several chunks
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	rand.New(rand.NewSource(1)).Read(img.Pix)
+
+	got := kittyTransmit(0x123456, img)
+	chunks := regexp.MustCompile("\x1b_G([^;]*);([^\x1b]*)\x1b\\\\").FindAllStringSubmatch(got, -1)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want more than one", len(chunks))
+	}
+	if first := "a=T,U=1,f=100,t=d,i=1193046,c=2,r=1,q=2,m=1"; chunks[0][1] != first {
+		t.Errorf("first chunk keys = %q, want %q", chunks[0][1], first)
+	}
+
+	var data strings.Builder
+	for i, chunk := range chunks {
+		if i > 0 {
+			want := "m=1"
+			if i == len(chunks)-1 {
+				want = "m=0"
+			}
+			if chunk[1] != want {
+				t.Errorf("chunk %d keys = %q, want %q", i, chunk[1], want)
+			}
+		}
+		if len(chunk[2]) > 4096 {
+			t.Errorf("chunk %d has %d bytes", i, len(chunk[2]))
+		}
+		data.WriteString(chunk[2])
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data.String())
+	if err != nil {
+		t.Fatalf("decoding chunks: %v", err)
+	}
+	sent, err := png.Decode(strings.NewReader(string(decoded)))
+	if err != nil {
+		t.Fatalf("decoding PNG: %v", err)
+	}
+	if sent.Bounds() != img.Bounds() {
+		t.Errorf("sent image is %v, want %v", sent.Bounds(), img.Bounds())
+	}
+}
+
+func TestKittyPlaceholders(t *testing.T) {
+	want := "\x1b[38;2;18;52;86m\U0010EEEE\u0305\u0305\U0010EEEE\u0305\u030D\x1b[39m"
+	if got := kittyPlaceholders(0x123456); got != want {
+		t.Errorf("kittyPlaceholders = %q, want %q", got, want)
+	}
+}
+
+func TestKittyImageID(t *testing.T) {
+	id := kittyImageID("icon data")
+	if id == 0 || id > 0xffffff {
+		t.Errorf("id = %d, want 1 to 0xffffff", id)
+	}
+	if kittyImageID("icon data") != id {
+		t.Error("id changed for the same data")
+	}
+}
+
+func TestSixelOutput(t *testing.T) {
+	cell := "  \x1b[2D\x1bP0;1;0q#1~\x1b\\"
+	frames := []struct {
+		name  string
+		write string
+		want  string
+	}{
+		{
+			name:  "first frame",
+			write: "\x1b[Ha" + cell + "1\r\nstatic\r\nend\x1b[3;1H",
+			want:  "\x1b[Ha" + cell + "1\r\nstatic\r\nend\x1b[3;1H",
+		},
+		{
+			name:  "image still on screen",
+			write: "\x1b[Ha" + cell + "2\r\n\x1b[B\x1b[3;1H",
+			want:  "\x1b[Ha\x1b[2C2\r\n\x1b[B\x1b[3;1H",
+		},
+		{
+			name:  "image moved",
+			write: "\x1b[Hab" + cell + "3\r\n\x1b[B\x1b[3;1H",
+			want:  "\x1b[Hab" + cell + "3\r\n\x1b[B\x1b[3;1H",
+		},
+		{
+			name:  "other line written",
+			write: "\x1b[H\x1b[B\x1b[Bnew end\x1b[3;1H",
+			want:  "\x1b[H\x1b[B\x1b[Bnew end\x1b[3;1H",
+		},
+		{
+			name:  "image kept on a skipped line",
+			write: "\x1b[Hab" + cell + "4\r\n\x1b[B\x1b[3;1H",
+			want:  "\x1b[Hab\x1b[2C4\r\n\x1b[B\x1b[3;1H",
+		},
+		{
+			name:  "cleared screen",
+			write: "\x1b[2J",
+			want:  "\x1b[2J",
+		},
+		{
+			name:  "after clearing",
+			write: "\x1b[Hab" + cell + "4\r\n\x1b[B\x1b[3;1H",
+			want:  "\x1b[Hab" + cell + "4\r\n\x1b[B\x1b[3;1H",
+		},
+		{
+			name:  "repaint",
+			write: "\x1b[Hab" + cell + "5\r\nstatic\r\nend\x1b[3;1H",
+			want:  "\x1b[Hab" + cell + "5\r\nstatic\r\nend\x1b[3;1H",
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "out")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	out := &sixelOutput{File: f, cells: []string{cell}}
+
+	var want strings.Builder
+	for _, frame := range frames {
+		n, err := out.Write([]byte(frame.write))
+		if err != nil || n != len(frame.write) {
+			t.Fatalf("%s: Write = %d, %v", frame.name, n, err)
+		}
+		want.WriteString(frame.want)
+
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want.String() {
+			t.Fatalf("%s: wrote %q, want %q", frame.name, got, want.String())
+		}
+	}
+}
@@ -10,10 +10,52 @@ import (
 )
 
 func main() {
-	if len(os.Args) == 3 && os.Args[1] == "--new" {
+	if len(os.Args) >= 2 && os.Args[1] == "resets" {
+		if err := runResetsCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "layout" {
+		if err := runLayoutCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "convert" {
+		if err := runConvertCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) >= 2 && os.Args[1] == "import" {
+		if err := runImportCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) >= 3 && os.Args[1] == "--new" {
 		// Create new LSS file
 		filename := os.Args[2]
 		state := sugarSplitCore.CreateBlankRun("New Game", "Any%")
+		switch {
+		case len(os.Args) == 5 && os.Args[3] == "--from-list":
+			if err := setSplitsFromList(state, os.Args[4]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		case len(os.Args) != 3:
+			fmt.Println("Usage: sugarSplit --new <filename.lss> [--from-list <splits.txt>]")
+			os.Exit(1)
+		}
 		err := sugarSplitCore.SaveRun(state, filename)
 		if err != nil {
 			fmt.Printf("Error creating file: %v\n", err)
@@ -21,11 +63,14 @@ func main() {
 		}
 		fmt.Printf("Created %s\n", filename)
 
-		// Open in edit mode
-		m := initialModel(filename)
-		m.mode = modeEditSplits
-		p := tea.NewProgram(m)
-		if err := p.Start(); err != nil {
+		// Open in edit mode on the run page to name the game and category
+		m := initialModel(filename).enterEditMode()
+		m.editPage = editPageRun
+		m.icons.sendSetup()
+		p := tea.NewProgram(m, tea.WithOutput(m.icons.output(os.Stdout)))
+		err = p.Start()
+		m.icons.sendTeardown()
+		if err != nil {
 			fmt.Printf("Error running program: %v\n", err)
 			os.Exit(1)
 		}
@@ -34,14 +79,21 @@ func main() {
 
 	if len(os.Args) != 2 {
 		fmt.Println("Usage: sugarSplit <filename.lss>")
-		fmt.Println("       sugarSplit --new <filename.lss>")
+		fmt.Println("       sugarSplit --new <filename.lss> [--from-list <splits.txt>]")
+		fmt.Println("       sugarSplit resets <filename.lss>")
+		fmt.Println("       sugarSplit layout <filename.lsl>")
+		fmt.Println("       sugarSplit convert <input.lss|input.json> <output.lss|output.json>")
+		fmt.Println("       sugarSplit import <file> [output.lss]")
 		os.Exit(1)
 	}
 
 	m := initialModel(os.Args[1])
-	p := tea.NewProgram(m)
+	m.icons.sendSetup()
+	p := tea.NewProgram(m, tea.WithOutput(m.icons.output(os.Stdout)))
 
-	if err := p.Start(); err != nil {
+	err := p.Start()
+	m.icons.sendTeardown()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
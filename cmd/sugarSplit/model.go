@@ -15,6 +15,27 @@ type appMode int
 const (
 	modeNormal appMode = iota
 	modeEditSplits
+	modeHistory
+	modeStats
+)
+
+type editPage int
+
+const (
+	editPageSplits editPage = iota
+	editPageRun
+)
+
+// editColumn is the value of a split that is edited on the splits page
+type editColumn int
+
+const (
+	editColumnName editColumn = iota
+	editColumnPBSplit
+	editColumnPBSegment
+	editColumnGold
+	// editColumnImport is a split list that replaces the splits
+	editColumnImport
 )
 
 type resetState int
@@ -26,16 +47,56 @@ const (
 
 type tickMsg time.Time
 
+// predictionSeed seeds the PB predictor so its estimate doesn't flicker between frames
+const predictionSeed = 1
+
 type model struct {
 	run           *sugarSplitCore.Run
 	width, height int
 	resetState    resetState
 	filename      string
 	mode          appMode
+	predictor     *sugarSplitCore.Predictor
+	// sessionStart is when sugarSplit was opened, for the session timer
+	sessionStart time.Time
+	// finishedAttempts is counted for the title whenever the splits change
+	finishedAttempts int
+	countedState     *sugarSplitCore.LiveSplitState
+	// icons draws the game and segment icons, prepared once at startup
+	icons *iconSet
 	// Edit mode fields
-	editIndex int
-	editInput string
-	editing   bool
+	editState *sugarSplitCore.LiveSplitState
+	// editHistory undoes and redoes the edits to editState
+	editHistory *sugarSplitCore.EditHistory
+	editIndex   int
+	editInput   textInput
+	editing     bool
+	editError   string
+	// editRecorded is set while typing finishes an edit that's already
+	// recorded for undo, like the name of a cut split
+	editRecorded bool
+	// editColumn is the value of the selected split being edited
+	editColumn editColumn
+	// editPage is the page of edit mode that is shown, editField the
+	// selected run field or variable on the run page
+	editPage  editPage
+	editField int
+	// editScroll is the first split shown, kept when edit mode is left so
+	// coming back opens where you were
+	editScroll int
+	// History mode fields
+	history       []sugarSplitCore.AttemptSummary
+	historyIndex  int
+	historyDetail bool
+	historyScroll int
+	// Stats mode fields
+	stats        []statsRow
+	statsSort    statsColumn
+	statsReverse bool
+	statsScroll  int
+	// Survival chart shown instead of the table
+	resets        []sugarSplitCore.ResetPoint
+	statsSurvival bool
 }
 
 func initialModel(filename string) model {
@@ -50,14 +111,65 @@ func initialModel(filename string) model {
 		fmt.Printf("Error creating run: %v\n", err)
 		os.Exit(1)
 	}
+	applyColorProfile(run.Theme)
+
+	if state.LayoutPath != "" && run.UIConfig.SplitsLayout {
+		loadSplitsLayout(run, filename, state.LayoutPath)
+	}
 
 	return model{
-		run:        run,
-		resetState: noReset,
-		filename:   filename,
-		mode:       modeNormal,
-		editIndex:  0,
+		run:          run,
+		resetState:   noReset,
+		filename:     filename,
+		mode:         modeNormal,
+		predictor:    sugarSplitCore.NewPredictor(state, predictionSeed),
+		sessionStart: time.Now(),
+		icons:        newIconSet(state, run.UIConfig.Icons),
+		editIndex:    0,
+	}
+}
+
+// loadSplitsLayout replaces the configured components with the LiveSplit
+// layout the splits file refers to. Problems are printed and the configured
+// components are kept; they show up once the timer is closed.
+func loadSplitsLayout(run *sugarSplitCore.Run, filename, layoutPath string) {
+	path, err := sugarSplitCore.ResolveLayoutPath(filename, layoutPath)
+	if err != nil {
+		fmt.Printf("Ignoring layout: %v\n", err)
+		return
+	}
+
+	config, warnings, err := sugarSplitCore.LoadLayout(path, run.UIConfig)
+	for _, warning := range warnings {
+		fmt.Printf("Layout %s: %s\n", path, warning)
+	}
+	if err != nil {
+		fmt.Printf("Ignoring layout: %v\n", err)
+		return
+	}
+	run.UIConfig = config
+}
+
+// updatePredictor prepares a new predictor when the splits have changed
+func (m model) updatePredictor() model {
+	if state := m.run.State(); m.predictor == nil || m.predictor.State() != state {
+		m.predictor = sugarSplitCore.NewPredictor(state, predictionSeed)
+	}
+	return m
+}
+
+// updateAttemptCount counts the finished attempts when the splits have changed
+func (m model) updateAttemptCount() model {
+	if state := m.run.State(); m.countedState != state {
+		m.countedState = state
+		m.finishedAttempts = 0
+		for _, attempt := range state.AttemptSummaries() {
+			if attempt.Finished {
+				m.finishedAttempts++
+			}
+		}
 	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
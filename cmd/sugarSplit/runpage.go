@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// runFieldWidth is the width of the labels on the run page
+const runFieldWidth = 10
+
+// runPageRows is the number of rows of the run page: the run fields and the
+// speedrun.com variables
+func (m model) runPageRows() int {
+	return len(sugarSplitCore.RunFields) + len(m.editState.Metadata.Variables.Variable)
+}
+
+// variableIndex returns the variable the selected row of the run page is,
+// or -1 for a run field
+func (m model) variableIndex() int {
+	if m.editField < len(sugarSplitCore.RunFields) {
+		return -1
+	}
+	return m.editField - len(sugarSplitCore.RunFields)
+}
+
+// updateRunPage handles the keys of the run page of edit mode
+func (m model) updateRunPage(key string) model {
+	switch key {
+	case "up", "k":
+		if m.editField > 0 {
+			m.editField--
+		}
+	case "down", "j":
+		if m.editField < m.runPageRows()-1 {
+			m.editField++
+		}
+	case "r":
+		// Edit the selected field, the emulator flag is toggled instead
+		if variable := m.variableIndex(); variable >= 0 {
+			v := m.editState.Metadata.Variables.Variable[variable]
+			m.editing = true
+			m.editInput = newTextInput(v.Name + "=" + v.Value)
+		} else if field := sugarSplitCore.RunFields[m.editField]; field == sugarSplitCore.FieldEmulator {
+			m.recordEdit()
+			m.editState.Metadata.Platform.UsesEmulator = !m.editState.Metadata.Platform.UsesEmulator
+		} else {
+			m.editing = true
+			m.editInput = newTextInput(m.editState.Field(field))
+		}
+	case "a":
+		// Add a variable, it's created once it has a name
+		m.editField = m.runPageRows()
+		m.editing = true
+		m.editInput = newTextInput("")
+	case "d":
+		if variable := m.variableIndex(); variable >= 0 {
+			m.recordEdit()
+			m.editState.RemoveVariable(variable)
+			m = m.clampEditCursors()
+		}
+	}
+	return m
+}
+
+// commitRunField applies the text being edited to the selected run field or
+// variable. Variables are written as name=value.
+func (m model) commitRunField() error {
+	variable := m.variableIndex()
+	if variable < 0 {
+		return m.editState.SetField(sugarSplitCore.RunFields[m.editField], m.editInput.String())
+	}
+
+	name, value, ok := strings.Cut(m.editInput.String(), "=")
+	if !ok {
+		return fmt.Errorf("write variables as name=value")
+	}
+	return m.editState.SetVariable(variable, name, value)
+}
+
+// renderRunPage lists the run fields and variables with the selected one
+// highlighted, and the text input while editing
+func (m model) renderRunPage(styles Styles) []string {
+	var lines []string
+
+	row := func(index int, label, value string) {
+		if index == m.editField && m.editing {
+			value = m.editInput.view(m.contentWidth() - 3 - runFieldWidth)
+		}
+		line := fmt.Sprintf("  %-*s %s", runFieldWidth, label, value)
+		if index == m.editField {
+			line = fmt.Sprintf("> %-*s %s", runFieldWidth, label, value)
+			lines = append(lines, styles.currentSegment.Render(line))
+		} else {
+			lines = append(lines, styles.segment.Render(line))
+		}
+	}
+
+	for i, field := range sugarSplitCore.RunFields {
+		row(i, string(field), m.editState.Field(field))
+	}
+	for i, variable := range m.editState.Metadata.Variables.Variable {
+		row(len(sugarSplitCore.RunFields)+i, "Variable", variable.Name+" = "+variable.Value)
+	}
+	// A variable that is being added has no row yet
+	if m.editField == m.runPageRows() {
+		row(m.editField, "Variable", "")
+	}
+
+	return lines
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+type statsColumn int
+
+const (
+	statsBySegment statsColumn = iota
+	statsByGold
+	statsByAverage
+	statsByMedian
+	statsByStdDev
+	statsByWorst
+	statsByAttempts
+	statsByResets
+	statsByConsistency
+	statsColumnCount
+)
+
+var statsColumnNames = []string{"Segment", "Gold", "Average", "Median", "StdDev", "Worst", "Reached", "Resets", "Cons."}
+
+// statsColumnWidths are the cell widths of the columns after the name, with
+// room for the sort arrow
+var statsColumnWidths = []int{10, 10, 10, 10, 10, 8, 7, 6}
+
+// statsRow is a segment's statistics along with its position in the run
+type statsRow struct {
+	index int
+	sugarSplitCore.SegmentStats
+}
+
+func (m model) enterStatsMode() model {
+	stats := m.run.State().SegmentStatistics()
+
+	m.stats = make([]statsRow, len(stats))
+	for i, s := range stats {
+		m.stats[i] = statsRow{index: i, SegmentStats: s}
+	}
+
+	m.resets = m.run.State().ResetAnalysis()
+	m.mode = modeStats
+	m.statsScroll = 0
+	return m
+}
+
+func (m model) updateStatsMode(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "t":
+			m.stats = nil
+			m.resets = nil
+			m.mode = modeNormal
+		case "tab":
+			m.statsSurvival = !m.statsSurvival
+		case "s", "right", "l":
+			m.statsSort = (m.statsSort + 1) % statsColumnCount
+		case "left", "h":
+			m.statsSort = (m.statsSort + statsColumnCount - 1) % statsColumnCount
+		case "S", "r":
+			m.statsReverse = !m.statsReverse
+		case "up", "k":
+			if m.statsScroll > 0 {
+				m.statsScroll--
+			}
+		case "down", "j":
+			if m.statsScroll < len(m.stats)-1 {
+				m.statsScroll++
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+// sortedStats returns the statistics ordered by the selected column
+func (m model) sortedStats() []statsRow {
+	rows := append([]statsRow(nil), m.stats...)
+
+	key := func(r statsRow) float64 {
+		switch m.statsSort {
+		case statsByGold:
+			return float64(r.Gold)
+		case statsByAverage:
+			return float64(r.Average)
+		case statsByMedian:
+			return float64(r.Median)
+		case statsByStdDev:
+			return float64(r.StdDev)
+		case statsByWorst:
+			return float64(r.Worst)
+		case statsByAttempts:
+			return float64(r.Attempts)
+		case statsByResets:
+			return float64(r.Resets)
+		case statsByConsistency:
+			return r.Consistency
+		}
+		return float64(r.index)
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		if m.statsReverse {
+			return key(rows[a]) > key(rows[b])
+		}
+		return key(rows[a]) < key(rows[b])
+	})
+	return rows
+}
+
+func (m model) renderStatsMode() string {
+	var s strings.Builder
+	styles := m.styles()
+	state := m.run.State()
+
+	title := "Segment Statistics"
+	if m.statsSurvival {
+		title = "Run Survival"
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.title.Render(title))
+	s.WriteString("\n")
+	s.WriteString(styles.title.Render(state.GameName + " - " + state.CategoryName))
+	s.WriteString("\n\n")
+
+	if m.statsSurvival {
+		return s.String() + m.renderSurvival(styles)
+	}
+
+	// The name takes what the other columns and the padding leave
+	nameWidth := m.contentWidth()
+	for _, width := range statsColumnWidths {
+		nameWidth -= width + 1
+	}
+	if nameWidth < 10 {
+		nameWidth = 10
+	}
+
+	// Header with the sort column marked
+	headers := make([]string, len(statsColumnNames))
+	for i, name := range statsColumnNames {
+		if statsColumn(i) == m.statsSort {
+			if m.statsReverse {
+				name += "↓"
+			} else {
+				name += "↑"
+			}
+		}
+		headers[i] = name
+	}
+	// The sort arrows are wider in bytes than in cells, so pad by cells
+	header := fitWidth(headers[0], nameWidth)
+	for i, width := range statsColumnWidths {
+		header += " " + lipgloss.PlaceHorizontal(width, lipgloss.Right, headers[i+1])
+	}
+	s.WriteString(styles.segment.Render(styles.muted.Render(header)))
+	s.WriteString("\n")
+
+	rows := m.sortedStats()
+	lines := m.height - 9
+	if lines < 1 {
+		lines = 1
+	}
+	start := m.statsScroll
+	if start > len(rows)-lines {
+		start = max(0, len(rows)-lines)
+	}
+	end := min(start+lines, len(rows))
+
+	for _, row := range rows[start:end] {
+		consistency := "-"
+		if row.Samples > 1 {
+			consistency = fmt.Sprintf("%.0f%%", row.Consistency)
+		}
+
+		line := fmt.Sprintf("%s %10s %10s %10s %10s %10s %8d %7d %6s", fitWidth(row.Name, nameWidth),
+			statDuration(row.Gold), statDuration(row.Average), statDuration(row.Median),
+			statDuration(row.StdDev), statDuration(row.Worst), row.Attempts, row.Resets, consistency)
+		s.WriteString(styles.segment.Render(line))
+		s.WriteString("\n")
+	}
+
+	// Calculate padding to push controls to bottom
+	contentHeight := 6 + (end - start) + 2
+	if m.height > contentHeight {
+		s.WriteString(strings.Repeat("\n", m.height-contentHeight))
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.controls.Render("s/h/l: Sort Column | r: Reverse | j/k: Scroll | Tab: Survival | Esc: Back"))
+
+	return s.String()
+}
+
+// renderSurvival draws the share of attempts surviving each split as a bar
+// chart, marking the split that ends the most runs
+func (m model) renderSurvival(styles Styles) string {
+	var s strings.Builder
+
+	nameWidth := 20
+	statsWidth := 22
+	barWidth := m.width - nameWidth - statsWidth - 4
+	if barWidth < 10 {
+		barWidth = 10
+	}
+
+	deadliest := -1
+	for i, point := range m.resets {
+		if point.Resets > 0 && (deadliest < 0 || point.Resets > m.resets[deadliest].Resets) {
+			deadliest = i
+		}
+	}
+
+	lines := m.height - 8
+	if lines < 1 {
+		lines = 1
+	}
+	start := m.statsScroll
+	if start > len(m.resets)-lines {
+		start = max(0, len(m.resets)-lines)
+	}
+	end := min(start+lines, len(m.resets))
+
+	for i := start; i < end; i++ {
+		point := m.resets[i]
+		filled := int(point.Survival*float64(barWidth) + 0.5)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+
+		stats := fmt.Sprintf("%4.0f%% %4d resets %3.0f%%", point.Survival*100, point.Resets, point.ResetRate*100)
+		name := fitWidth(point.Name, nameWidth)
+
+		if i == deadliest {
+			bar = styles.behind.Render(bar)
+		} else {
+			bar = styles.ahead.Render(bar)
+		}
+		s.WriteString(styles.segment.Render(name + " " + bar + " " + stats))
+		s.WriteString("\n")
+	}
+
+	// Calculate padding to push controls to bottom
+	contentHeight := 5 + (end - start) + 2
+	if m.height > contentHeight {
+		s.WriteString(strings.Repeat("\n", m.height-contentHeight))
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.controls.Render("j/k: Scroll | Tab: Statistics | Esc: Back"))
+
+	return s.String()
+}
+
+// statDuration formats a statistic, showing a dash when there is no data
+func statDuration(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return sugarSplitCore.FormatDuration(d)
+}
@@ -1,58 +1,156 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
-
-// Color constants
-const (
-	ColorPrimary     = lipgloss.Color("205") // Pink/magenta
-	ColorAhead       = lipgloss.Color("82")  // Green
-	ColorBehind      = lipgloss.Color("196") // Red
-	ColorGold        = lipgloss.Color("220") // Gold/yellow
-	ColorMuted       = lipgloss.Color("244") // Gray
-	ColorHighlightBg = lipgloss.Color("17")  // Dark blue
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"sugarSplit/pkg/sugarSplitCore"
 )
 
+// Palette holds the theme's colors converted for lipgloss
+type Palette struct {
+	aheadGaining  lipgloss.TerminalColor
+	aheadLosing   lipgloss.TerminalColor
+	behindGaining lipgloss.TerminalColor
+	behindLosing  lipgloss.TerminalColor
+	gold          lipgloss.TerminalColor
+	pb            lipgloss.TerminalColor
+	highlight     lipgloss.TerminalColor
+	text          lipgloss.TerminalColor
+	title         lipgloss.TerminalColor
+	muted         lipgloss.TerminalColor
+}
+
 // Styles holds all UI styles
 type Styles struct {
+	colors         Palette
 	title          lipgloss.Style
 	segment        lipgloss.Style
 	currentSegment lipgloss.Style
 	ahead          lipgloss.Style
+	aheadLosing    lipgloss.Style
 	behind         lipgloss.Style
+	behindGaining  lipgloss.Style
 	gold           lipgloss.Style
-	pb             lipgloss.Style
+	muted          lipgloss.Style
 	timer          lipgloss.Style
 	controls       lipgloss.Style
 }
 
-func initializeStyles(width int) Styles {
+// themeColor converts a theme color to lipgloss. A color with every fallback
+// set is used as is, otherwise lipgloss converts the one value that is set
+// to what the terminal supports.
+func themeColor(c sugarSplitCore.ThemeColor) lipgloss.TerminalColor {
+	if c.TrueColor != "" && c.ANSI256 != "" && c.ANSI != "" {
+		return lipgloss.CompleteColor{TrueColor: c.TrueColor, ANSI256: c.ANSI256, ANSI: c.ANSI}
+	}
+	for _, value := range []string{c.TrueColor, c.ANSI256, c.ANSI} {
+		if value != "" {
+			return lipgloss.Color(value)
+		}
+	}
+	return lipgloss.NoColor{}
+}
+
+func newPalette(theme *sugarSplitCore.Theme) Palette {
+	return Palette{
+		aheadGaining:  themeColor(theme.AheadGaining),
+		aheadLosing:   themeColor(theme.AheadLosing),
+		behindGaining: themeColor(theme.BehindGaining),
+		behindLosing:  themeColor(theme.BehindLosing),
+		gold:          themeColor(theme.Gold),
+		pb:            themeColor(theme.PB),
+		highlight:     themeColor(theme.Highlight),
+		text:          themeColor(theme.Text),
+		title:         themeColor(theme.Title),
+		muted:         themeColor(theme.Muted),
+	}
+}
+
+// applyColorProfile forces the theme's color profile instead of the detected one
+func applyColorProfile(theme *sugarSplitCore.Theme) {
+	switch theme.ColorProfile {
+	case sugarSplitCore.ColorProfileTrueColor:
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case sugarSplitCore.ColorProfile256:
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case sugarSplitCore.ColorProfile16:
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+}
+
+// styles returns the styles for the current terminal width and theme
+func (m model) styles() Styles {
+	return initializeStyles(m.width, m.run.Theme)
+}
+
+func initializeStyles(width int, theme *sugarSplitCore.Theme) Styles {
 	fullWidth := width
 	if fullWidth < 40 {
 		fullWidth = 40
 	}
 
+	colors := newPalette(theme)
+
 	return Styles{
+		colors: colors,
 		title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(ColorPrimary).
+			Foreground(colors.title).
 			Align(lipgloss.Center).
 			Width(fullWidth),
 		segment: lipgloss.NewStyle().
 			Width(fullWidth).
-			Padding(0, 1),
+			Padding(0, 1).
+			Foreground(colors.text),
 		currentSegment: lipgloss.NewStyle().
 			Width(fullWidth).
 			Padding(0, 1).
-			Background(ColorHighlightBg),
-		ahead:  lipgloss.NewStyle().Foreground(ColorAhead),
-		behind: lipgloss.NewStyle().Foreground(ColorBehind),
-		gold:   lipgloss.NewStyle().Foreground(ColorGold),
-		pb:     lipgloss.NewStyle().Foreground(ColorMuted),
+			Foreground(colors.text).
+			Background(colors.highlight),
+		ahead:         lipgloss.NewStyle().Foreground(colors.aheadGaining),
+		aheadLosing:   lipgloss.NewStyle().Foreground(colors.aheadLosing),
+		behind:        lipgloss.NewStyle().Foreground(colors.behindLosing),
+		behindGaining: lipgloss.NewStyle().Foreground(colors.behindGaining),
+		gold:          lipgloss.NewStyle().Foreground(colors.gold),
+		muted:         lipgloss.NewStyle().Foreground(colors.muted),
 		timer: lipgloss.NewStyle().
 			Bold(true).
 			Align(lipgloss.Center),
 		controls: lipgloss.NewStyle().
 			Width(fullWidth).
+			Foreground(colors.text).
 			Align(lipgloss.Center),
 	}
 }
+
+// delta returns the style for a delta in the given state
+func (s Styles) delta(state sugarSplitCore.DeltaState) lipgloss.Style {
+	switch state {
+	case sugarSplitCore.DeltaAheadGaining:
+		return s.ahead
+	case sugarSplitCore.DeltaAheadLosing:
+		return s.aheadLosing
+	case sugarSplitCore.DeltaBehindGaining:
+		return s.behindGaining
+	case sugarSplitCore.DeltaBehindLosing:
+		return s.behind
+	}
+	return lipgloss.NewStyle()
+}
+
+// deltaColor returns the color for a delta in the given state, or fallback
+// when there is no delta
+func (s Styles) deltaColor(state sugarSplitCore.DeltaState, fallback lipgloss.TerminalColor) lipgloss.TerminalColor {
+	switch state {
+	case sugarSplitCore.DeltaAheadGaining:
+		return s.colors.aheadGaining
+	case sugarSplitCore.DeltaAheadLosing:
+		return s.colors.aheadLosing
+	case sugarSplitCore.DeltaBehindGaining:
+		return s.colors.behindGaining
+	case sugarSplitCore.DeltaBehindLosing:
+		return s.colors.behindLosing
+	}
+	return fallback
+}
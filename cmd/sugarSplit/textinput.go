@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// textInput is a line of editable text with a cursor. It works on runes, so
+// names in any script can be typed and edited.
+type textInput struct {
+	value  []rune
+	cursor int
+	// multiline keeps the line breaks of pasted text, shown as ↵
+	multiline bool
+}
+
+// newTextInput starts editing value with the cursor at its end
+func newTextInput(value string) textInput {
+	runes := []rune(value)
+	return textInput{value: runes, cursor: len(runes)}
+}
+
+// String returns the text
+func (t textInput) String() string {
+	return string(t.value)
+}
+
+// update applies a key to the text. Pasted text arrives as runes, line
+// breaks in it become spaces unless the input is multiline.
+func (t textInput) update(msg tea.KeyMsg) textInput {
+	switch msg.String() {
+	case "left", "ctrl+b":
+		t.cursor = max(t.cursor-1, 0)
+	case "right", "ctrl+f":
+		t.cursor = min(t.cursor+1, len(t.value))
+	case "alt+left", "ctrl+left", "alt+b":
+		t.cursor = t.wordStart()
+	case "alt+right", "ctrl+right", "alt+f":
+		t.cursor = t.wordEnd()
+	case "home", "ctrl+a":
+		t.cursor = 0
+	case "end", "ctrl+e":
+		t.cursor = len(t.value)
+	case "backspace", "ctrl+h":
+		t = t.delete(max(t.cursor-1, 0), t.cursor)
+	case "delete", "ctrl+d":
+		t = t.delete(t.cursor, min(t.cursor+1, len(t.value)))
+	case "ctrl+w", "alt+backspace":
+		t = t.delete(t.wordStart(), t.cursor)
+	case "alt+d", "alt+delete":
+		t = t.delete(t.cursor, t.wordEnd())
+	case "ctrl+u":
+		t = t.delete(0, t.cursor)
+	case "ctrl+k":
+		t = t.delete(t.cursor, len(t.value))
+	case " ":
+		t = t.insert([]rune{' '})
+	default:
+		if msg.Type == tea.KeyRunes && !msg.Alt {
+			t = t.insert(msg.Runes)
+		}
+	}
+	return t
+}
+
+// insert adds runes at the cursor, leaving out control characters.
+// Terminals paste line breaks as \r, they're read like \n.
+func (t textInput) insert(runes []rune) textInput {
+	var inserted []rune
+	for i, r := range runes {
+		if r == '\r' {
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				continue
+			}
+			r = '\n'
+		}
+
+		switch {
+		case (r == '\n' || r == '\t') && t.multiline:
+			inserted = append(inserted, r)
+		case r == '\n' || r == '\t':
+			inserted = append(inserted, ' ')
+		case unicode.IsPrint(r):
+			inserted = append(inserted, r)
+		}
+	}
+
+	value := make([]rune, 0, len(t.value)+len(inserted))
+	value = append(value, t.value[:t.cursor]...)
+	value = append(value, inserted...)
+	t.value = append(value, t.value[t.cursor:]...)
+	t.cursor += len(inserted)
+	return t
+}
+
+// delete removes the runes from start to end and puts the cursor there
+func (t textInput) delete(start, end int) textInput {
+	value := make([]rune, 0, len(t.value))
+	value = append(value, t.value[:start]...)
+	t.value = append(value, t.value[end:]...)
+	t.cursor = start
+	return t
+}
+
+// wordStart returns the start of the word before the cursor
+func (t textInput) wordStart() int {
+	i := t.cursor
+	for i > 0 && unicode.IsSpace(t.value[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(t.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+// wordEnd returns the end of the word after the cursor
+func (t textInput) wordEnd() int {
+	i := t.cursor
+	for i < len(t.value) && unicode.IsSpace(t.value[i]) {
+		i++
+	}
+	for i < len(t.value) && !unicode.IsSpace(t.value[i]) {
+		i++
+	}
+	return i
+}
+
+// view draws the text in at most width cells with the cursor in reverse
+// video. Text that doesn't fit scrolls to keep the cursor in view.
+func (t textInput) view(width int) string {
+	width = max(width, 1)
+	// Line breaks and tabs are drawn as a symbol of their own
+	shown := make([]rune, len(t.value))
+	cells := make([]int, len(t.value))
+	for i, r := range t.value {
+		switch r {
+		case '\n':
+			r = '↵'
+		case '\t':
+			r = '→'
+		}
+		shown[i] = r
+		cells[i] = lipgloss.Width(string(r))
+	}
+
+	// The cursor takes a cell of its own at the end of the text
+	cursorWidth := 1
+	if t.cursor < len(t.value) {
+		cursorWidth = max(cells[t.cursor], 1)
+	}
+
+	// Show as much as fits before the cursor, then fill up after it
+	start, used := t.cursor, cursorWidth
+	for start > 0 && used+cells[start-1] <= width {
+		start--
+		used += cells[start]
+	}
+
+	var s strings.Builder
+	s.WriteString(string(shown[start:t.cursor]))
+
+	// Reverse video only, so the line keeps its colors around the cursor
+	under := " "
+	if t.cursor < len(t.value) {
+		under = string(shown[t.cursor])
+	}
+	s.WriteString("\x1b[7m" + under + "\x1b[27m")
+
+	for i := t.cursor + 1; i < len(t.value) && used+cells[i] <= width; i++ {
+		s.WriteRune(shown[i])
+		used += cells[i]
+	}
+	return s.String()
+}
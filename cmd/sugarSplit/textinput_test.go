@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// paste sends text to an input the way bubbletea passes on a bracketed paste
+func paste(t textInput, text string) textInput {
+	return t.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+}
+
+func TestTextInputPasteLineBreaks(t *testing.T) {
+	tests := []struct {
+		name      string
+		multiline bool
+		text      string
+		want      string
+	}{
+		{"carriage returns", true, "Forest\rCastle | 3:15\rBoss\r", "Forest\nCastle | 3:15\nBoss\n"},
+		{"windows line breaks", true, "Forest\r\nCastle\r\n", "Forest\nCastle\n"},
+		{"unix line breaks", true, "Forest\nCastle", "Forest\nCastle"},
+		{"single line", false, "Forest\rCastle\r\nBoss", "Forest Castle Boss"},
+		{"tabs", false, "Forest\t1:02", "Forest 1:02"},
+		{"control characters", true, "For\x1best\x00", "Forest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := paste(textInput{multiline: tt.multiline}, tt.text)
+			if got := input.String(); got != tt.want {
+				t.Errorf("pasted %q = %q, want %q", tt.text, got, tt.want)
+			}
+			if input.cursor != len([]rune(tt.want)) {
+				t.Errorf("cursor = %d, want the end", input.cursor)
+			}
+		})
+	}
+}
+
+func TestTextInputPasteSplitList(t *testing.T) {
+	input := paste(textInput{multiline: true}, "Forest | 1:02.50\rCastle | 3:15.00\rFinal Boss\r")
+
+	entries, err := sugarSplitCore.ReadSplitList(input.String())
+	if err != nil {
+		t.Fatalf("ReadSplitList: %v", err)
+	}
+	want := []string{"Forest", "Castle", "Final Boss"}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d splits, want %d", len(entries), len(want))
+	}
+	for i, name := range want {
+		if entries[i].Name != name {
+			t.Errorf("split %d = %q, want %q", i, entries[i].Name, name)
+		}
+	}
+}
+
+func TestTextInputEditing(t *testing.T) {
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	home := tea.KeyMsg{Type: tea.KeyHome}
+	backspace := tea.KeyMsg{Type: tea.KeyBackspace}
+	del := tea.KeyMsg{Type: tea.KeyDelete}
+	wordLeft := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}, Alt: true}
+	wordRight := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}, Alt: true}
+	deleteWord := tea.KeyMsg{Type: tea.KeyCtrlW}
+	deleteNextWord := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}, Alt: true}
+
+	tests := []struct {
+		name   string
+		value  string
+		keys   []tea.KeyMsg
+		want   string
+		cursor int
+	}{
+		{"backspace after an accented letter", "naïve", []tea.KeyMsg{left, left, backspace}, "nave", 2},
+		{"backspace a wide letter", "日本語", []tea.KeyMsg{left, left, backspace}, "本語", 0},
+		{"delete a wide letter", "日本", []tea.KeyMsg{home, del}, "本", 0},
+		{"delete an emoji", "a🙂b", []tea.KeyMsg{left, backspace}, "ab", 1},
+		{"type between wide letters", "日語", []tea.KeyMsg{left, runes("本")}, "日本語", 2},
+		{"stay at the end", "é", []tea.KeyMsg{right, right}, "é", 1},
+		{"word left", "Forêt 城 🙂", []tea.KeyMsg{wordLeft, wordLeft}, "Forêt 城 🙂", 6},
+		{"word right", "Forêt 城 🙂", []tea.KeyMsg{home, wordRight, wordRight}, "Forêt 城 🙂", 7},
+		{"delete word before", "Forêt 城 🙂", []tea.KeyMsg{deleteWord, deleteWord}, "Forêt ", 6},
+		{"delete word after", "日本 語", []tea.KeyMsg{home, deleteNextWord}, " 語", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := newTextInput(tt.value)
+			for _, key := range tt.keys {
+				input = input.update(key)
+			}
+			if got := input.String(); got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			if input.cursor != tt.cursor {
+				t.Errorf("cursor = %d, want %d", input.cursor, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestTextInputView(t *testing.T) {
+	const on, off = "\x1b[7m", "\x1b[27m"
+
+	tests := []struct {
+		name  string
+		input textInput
+		width int
+		want  string
+	}{
+		{"fits", newTextInput("Forêt"), 10, "Forêt" + on + " " + off},
+		{"scrolls to the cursor", newTextInput("日本語"), 5, "本語" + on + " " + off},
+		{"wide letter under the cursor", textInput{value: []rune("日本語")}, 4, on + "日" + off + "本"},
+		{"emoji before the cursor", textInput{value: []rune("🙂ab"), cursor: 1}, 10, "🙂" + on + "a" + off + "b"},
+		{"cut after the cursor", textInput{value: []rune("ab日本"), cursor: 1}, 4, "a" + on + "b" + off + "日"},
+		{"line breaks", textInput{value: []rune("a\nb\tc"), cursor: 5, multiline: true}, 10, "a↵b→c" + on + " " + off},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.input.view(tt.width); got != tt.want {
+				t.Errorf("view(%d) = %q, want %q", tt.width, got, tt.want)
+			}
+		})
+	}
+}
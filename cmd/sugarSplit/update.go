@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,68 +21,56 @@ func (m model) handleKey(key string) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case sugarSplitCore.ActionSplit:
-		if !m.run.Started {
-			m.run.Started = true
-			m.run.StartTime = time.Now()
-			m.run.CurrentSplit = 0
-			m.run.UpdateHotkeyAvailability()
-			return m, tick()
-		} else if !m.run.Completed {
-			if m.run.CurrentSplit < len(m.run.State.Segments.Segments) {
-				m.run.Split(m.run.CurrentTime)
-				m.run.UpdateHotkeyAvailability()
-			}
+		if !m.run.Snapshot().Started {
+			m.run.Start(time.Now())
+			return m, nil
 		}
+		m.run.Split(time.Now())
 
 	case sugarSplitCore.ActionReset:
-		if !m.run.ResettingState {
-			m.run.ResettingState = true
-			m.run.UpdateHotkeyAvailability()
-		}
+		m.run.BeginReset()
 
 	case sugarSplitCore.ActionConfirm:
-		if m.run.ResettingState {
-			m.run.Reset()
-			return m, nil
-		}
+		m.run.Reset()
 
 	case sugarSplitCore.ActionSaveReset:
-		if m.run.ResettingState {
-			err := m.run.SaveRun(m.filename)
-			if err != nil {
-				fmt.Printf("Error saving run: %v\n", err)
-			}
-			m.run.Reset()
-			return m, nil
+		err := m.run.SaveAndReset(m.filename)
+		if err != nil {
+			fmt.Printf("Error saving run: %v\n", err)
 		}
 
 	case sugarSplitCore.ActionUndo:
-		if m.run.Started && m.run.CurrentSplit > 0 && !m.run.Completed {
-			m.run.UndoSplit()
-			m.run.UpdateHotkeyAvailability()
-		} else if m.run.Completed {
-			m.run.UndoSplit()
-			m.run.StartTime = time.Now().Add(-m.run.CurrentTime)
-			m.run.UpdateHotkeyAvailability()
-			return m, tick()
-		}
+		m.run.UndoSplit(time.Now())
+
 	case sugarSplitCore.ActionSkip:
-		if m.run.Started && !m.run.Completed {
-			m.run.SkipSplit()
-			m.run.UpdateHotkeyAvailability()
+		m.run.SkipSplit()
+
+	case sugarSplitCore.ActionPause:
+		if m.run.Snapshot().Paused {
+			m.run.Resume(time.Now())
+		} else {
+			m.run.Pause(time.Now())
 		}
 
+	case sugarSplitCore.ActionComparison:
+		m.run.NextComparison()
+
 	case sugarSplitCore.ActionCancel:
-		if m.run.ResettingState {
-			m.run.ResettingState = false
-			m.run.UpdateHotkeyAvailability()
-		}
+		m.run.CancelReset()
 
 	case sugarSplitCore.ActionEdit:
-		if !m.run.Started && !m.run.Completed {
-			m.mode = modeEditSplits
-			m.editIndex = 0
-			return m, nil
+		if snapshot := m.run.Snapshot(); !snapshot.Started && !snapshot.Completed {
+			return m.enterEditMode(), nil
+		}
+
+	case sugarSplitCore.ActionHistory:
+		if snapshot := m.run.Snapshot(); !snapshot.Started && !snapshot.Completed {
+			return m.enterHistoryMode(), nil
+		}
+
+	case sugarSplitCore.ActionStats:
+		if snapshot := m.run.Snapshot(); !snapshot.Started && !snapshot.Completed {
+			return m.enterStatsMode(), nil
 		}
 	}
 
@@ -89,20 +78,30 @@ func (m model) handleKey(key string) (tea.Model, tea.Cmd) {
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// There is a single tick loop, started by Init. The other modes keep it
+	// going so the clock and session timer still run after leaving them.
+	if _, ok := msg.(tickMsg); ok && m.mode != modeNormal {
+		return m, tick()
+	}
+
 	// Handle edit mode separately
 	if m.mode == modeEditSplits {
 		return m.updateEditMode(msg)
 	}
+	if m.mode == modeHistory {
+		return m.updateHistoryMode(msg)
+	}
+	if m.mode == modeStats {
+		return m.updateStatsMode(msg)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKey(msg.String())
 
 	case tickMsg:
-		if m.run.Started && !m.run.ResettingState {
-			m.run.CurrentTime = time.Since(m.run.StartTime)
-		}
-		return m, tick()
+		m.run.Tick(time.Time(msg))
+		return m.updatePredictor().updateAttemptCount(), tick()
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -111,6 +110,104 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// enterEditMode switches to edit mode with a working copy of the splits, so
+// the run keeps using the saved splits until the edit is confirmed
+func (m model) enterEditMode() model {
+	m.mode = modeEditSplits
+	m.editState = m.run.State().Clone()
+	m.editHistory = &sugarSplitCore.EditHistory{}
+	m.editIndex = min(m.editIndex, len(m.editState.Segments.Segments)-1)
+	return m.scrollEditList()
+}
+
+// recordEdit saves the splits before an edit so it can be undone
+func (m model) recordEdit() {
+	m.editHistory.Record(m.editState.Clone())
+}
+
+// undoEdit reverts the last edit, keeping the cursors on existing rows
+func (m model) undoEdit() model {
+	m.editState, _ = m.editHistory.Undo(m.editState)
+	return m.clampEditCursors()
+}
+
+// redoEdit applies the last undone edit again
+func (m model) redoEdit() model {
+	m.editState, _ = m.editHistory.Redo(m.editState)
+	return m.clampEditCursors()
+}
+
+// clampEditCursors moves the edit mode cursors back onto the splits and run
+// fields after the number of them changed
+func (m model) clampEditCursors() model {
+	m.editIndex = min(m.editIndex, len(m.editState.Segments.Segments)-1)
+	m.editField = min(m.editField, m.runPageRows()-1)
+	return m
+}
+
+// commitEdit applies the text being edited to the selected split or run field
+func (m model) commitEdit() error {
+	if m.editPage == editPageRun {
+		return m.commitRunField()
+	}
+	if m.editIndex >= len(m.editState.Segments.Segments) {
+		return nil
+	}
+	if m.editColumn == editColumnName {
+		m.editState.RenameSegment(m.editIndex, m.editInput.String())
+		return nil
+	}
+	if m.editColumn == editColumnImport {
+		return m.importSplitList()
+	}
+
+	// An empty PB split time clears it, like a skipped split
+	if m.editColumn == editColumnPBSplit && strings.TrimSpace(m.editInput.String()) == "" {
+		return m.editState.SetPBSplitTime(m.editIndex, 0)
+	}
+	t, err := sugarSplitCore.ParseDuration(m.editInput.String())
+	if err != nil {
+		return err
+	}
+	switch m.editColumn {
+	case editColumnPBSplit:
+		return m.editState.SetPBSplitTime(m.editIndex, t)
+	case editColumnPBSegment:
+		return m.editState.SetPBSegmentTime(m.editIndex, t)
+	default:
+		return m.editState.SetGold(m.editIndex, t)
+	}
+}
+
+// importSplitList replaces the splits with the split list being edited:
+// pasted lines, or the path of a file with them
+func (m model) importSplitList() error {
+	entries, err := sugarSplitCore.ReadSplitList(m.editInput.String())
+	if err != nil {
+		return err
+	}
+	return m.editState.SetSegmentsFromList(entries)
+}
+
+// editTime returns a split's time as it's shown for editing, "" if there is none
+func editTime(t time.Duration) string {
+	if t <= 0 {
+		return ""
+	}
+	return sugarSplitCore.FormatDuration(t)
+}
+
+// editListHeight is the number of splits edit mode has room for
+func (m model) editListHeight() int {
+	return m.height - 10 // header, column titles and controls
+}
+
+// scrollEditList scrolls the edit mode split list to keep the cursor in view
+func (m model) scrollEditList() model {
+	m.editScroll = scrollOffset(m.editScroll, m.editIndex, len(m.editState.Segments.Segments), m.editListHeight())
+	return m
+}
+
 func (m model) updateEditMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -120,87 +217,158 @@ func (m model) updateEditMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.editing {
 			switch key {
 			case "enter":
-				// Commit the edit
-				if m.editIndex < len(m.run.State.Segments.Segments) {
-					m.run.State.RenameSegment(m.editIndex, m.editInput)
+				// Commit the edit, invalid input stays open to be fixed
+				before := m.editState.Clone()
+				if err := m.commitEdit(); err != nil {
+					m.editError = err.Error()
+					// A variable that can't be added is dropped along
+					// with its row, the error stays until the next key
+					if m.editPage == editPageRun && m.editField >= m.runPageRows() {
+						m.editing = false
+						m.editInput = textInput{}
+						return m.clampEditCursors(), nil
+					}
+					return m, nil
+				}
+				if !m.editRecorded {
+					m.editHistory.Record(before)
+				}
+				if m.editColumn == editColumnImport {
+					m.editIndex = 0
 				}
 				m.editing = false
-				m.editInput = ""
+				m.editRecorded = false
+				m.editColumn = editColumnName
+				m.editInput = textInput{}
+				m.editError = ""
 				return m, nil
 			case "esc":
 				m.editing = false
-				m.editInput = ""
-				return m, nil
-			case "backspace":
-				if len(m.editInput) > 0 {
-					m.editInput = m.editInput[:len(m.editInput)-1]
-				}
-				return m, nil
+				m.editRecorded = false
+				m.editColumn = editColumnName
+				m.editInput = textInput{}
+				m.editError = ""
+				// Drop the row of a variable that wasn't added
+				return m.clampEditCursors(), nil
 			default:
-				// Only add printable characters
-				if len(key) == 1 {
-					m.editInput += key
-				}
+				m.editInput = m.editInput.update(msg)
+				m.editError = ""
 				return m, nil
 			}
 		}
 
 		// Navigation and actions when not editing
+		m.editError = ""
 		switch key {
 		case "esc":
-			// Cancel - reload from file to discard changes
-			state, err := sugarSplitCore.LoadRun(m.filename)
-			if err == nil {
-				m.run.State = state
-				m.run.ReinitializeArrays()
-			}
+			// Cancel - drop the working copy to discard changes
+			m.editState = nil
 			m.mode = modeNormal
 			return m, nil
 		case "enter":
 			// Save and exit
-			err := sugarSplitCore.SaveRun(m.run.State, m.filename)
+			err := sugarSplitCore.SaveRun(m.editState, m.filename)
 			if err == nil {
+				m.run.SetState(m.editState)
+				m.editState = nil
 				m.mode = modeNormal
 			}
 			return m, nil
+		case "u", "ctrl+z":
+			return m.undoEdit(), nil
+		case "ctrl+r", "ctrl+y":
+			return m.redoEdit(), nil
+		case "tab":
+			if m.editPage == editPageSplits {
+				m.editPage = editPageRun
+			} else {
+				m.editPage = editPageSplits
+			}
+			return m, nil
+		}
+
+		if m.editPage == editPageRun {
+			return m.updateRunPage(key), nil
+		}
+
+		switch key {
 		case "up", "k":
 			if m.editIndex > 0 {
 				m.editIndex--
 			}
 		case "down", "j":
-			if m.editIndex < len(m.run.State.Segments.Segments)-1 {
+			if m.editIndex < len(m.editState.Segments.Segments)-1 {
 				m.editIndex++
 			}
 		case "r":
 			// Rename current segment
-			if m.editIndex < len(m.run.State.Segments.Segments) {
+			if m.editIndex < len(m.editState.Segments.Segments) {
 				m.editing = true
-				m.editInput = m.run.State.Segments.Segments[m.editIndex].Name
+				m.editColumn = editColumnName
+				m.editInput = newTextInput(m.editState.Segments.Segments[m.editIndex].Name)
 			}
+		case "p":
+			// Edit the PB split time
+			m.editing = true
+			m.editColumn = editColumnPBSplit
+			m.editInput = newTextInput(editTime(m.editState.PBSplitTime(m.editIndex)))
+		case "s":
+			// Edit the PB segment time
+			m.editing = true
+			m.editColumn = editColumnPBSegment
+			m.editInput = newTextInput(editTime(m.editState.PBSegmentTime(m.editIndex)))
+		case "g":
+			// Edit the gold
+			m.editing = true
+			m.editColumn = editColumnGold
+			m.editInput = newTextInput(editTime(m.editState.GoldTime(m.editIndex)))
+		case "i":
+			// Import a split list
+			m.editing = true
+			m.editColumn = editColumnImport
+			m.editInput = textInput{multiline: true}
 		case "a":
 			// Add new split after current
-			m.run.State.AddSegment(m.editIndex, "New Split")
-			m.run.ReinitializeArrays()
+			m.recordEdit()
+			m.editState.AddSegment(m.editIndex, "New Split")
 			m.editIndex++
 		case "d":
-			// Delete current split (but keep at least one)
-			if len(m.run.State.Segments.Segments) > 1 {
-				m.run.State.RemoveSegment(m.editIndex)
-				m.run.ReinitializeArrays()
-				if m.editIndex >= len(m.run.State.Segments.Segments) {
-					m.editIndex = len(m.run.State.Segments.Segments) - 1
+			// Delete current split (but keep at least one), its times go
+			// to a neighbour
+			if len(m.editState.Segments.Segments) > 1 {
+				m.recordEdit()
+				m.editState.DeleteSegment(m.editIndex)
+				if m.editIndex >= len(m.editState.Segments.Segments) {
+					m.editIndex = len(m.editState.Segments.Segments) - 1
 				}
 			}
+		case "m":
+			// Merge current split with the next, keeping both histories
+			if m.editIndex < len(m.editState.Segments.Segments)-1 {
+				m.recordEdit()
+				m.editState.MergeSegmentWithNext(m.editIndex)
+			}
+		case "c":
+			// Cut the current segment in two and name the new split, one
+			// undo takes back both
+			m.recordEdit()
+			m.editState.SplitSegment(m.editIndex, "New Split")
+			m.editing = true
+			m.editRecorded = true
+			m.editColumn = editColumnName
+			m.editInput = newTextInput("New Split")
 		case "K", "shift+up":
 			// Move split up
 			if m.editIndex > 0 {
-				m.run.State.MoveSegmentUp(m.editIndex)
+				m.recordEdit()
+				m.editState.MoveSegmentUp(m.editIndex)
 				m.editIndex--
 			}
 		case "J", "shift+down":
 			// Move split down
-			if m.editIndex < len(m.run.State.Segments.Segments)-1 {
-				m.run.State.MoveSegmentDown(m.editIndex)
+			if m.editIndex < len(m.editState.Segments.Segments)-1 {
+				m.recordEdit()
+				m.editState.MoveSegmentDown(m.editIndex)
 				m.editIndex++
 			}
 		}
@@ -210,5 +378,8 @@ func (m model) updateEditMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 	}
 
+	if m.mode == modeEditSplits {
+		m = m.scrollEditList()
+	}
 	return m, nil
 }
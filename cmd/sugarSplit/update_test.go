@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sugarSplit/pkg/sugarSplitCore"
+)
+
+// newEditModel opens edit mode on splits with the given names
+func newEditModel(names ...string) model {
+	state := sugarSplitCore.CreateBlankRun("Game", "Any%")
+	state.Segments.Segments = nil
+	for _, name := range names {
+		state.Segments.Segments = append(state.Segments.Segments, sugarSplitCore.NewSegment(name))
+	}
+	return model{
+		mode:        modeEditSplits,
+		editState:   state,
+		editHistory: &sugarSplitCore.EditHistory{},
+		width:       80,
+		height:      40,
+	}
+}
+
+// press sends keys to edit mode, one message per key
+func press(m model, keys ...tea.KeyMsg) model {
+	for _, key := range keys {
+		next, _ := m.updateEditMode(key)
+		m = next.(model)
+	}
+	return m
+}
+
+func runes(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+func TestCutSplitUndoesInOneStep(t *testing.T) {
+	m := newEditModel("Forest", "Castle")
+	m = press(m, runes("c"), tea.KeyMsg{Type: tea.KeyCtrlU}, runes("Bridge"), tea.KeyMsg{Type: tea.KeyEnter})
+
+	if n := len(m.editState.Segments.Segments); n != 3 || m.editState.Segments.Segments[0].Name != "Bridge" {
+		t.Fatalf("after cutting: %d splits, first %q", n, m.editState.Segments.Segments[0].Name)
+	}
+
+	m = press(m, runes("u"))
+	if n := len(m.editState.Segments.Segments); n != 2 {
+		t.Errorf("one undo left %d splits, want 2", n)
+	}
+	if m.editHistory.CanUndo() {
+		t.Error("the cut takes more than one undo")
+	}
+}
+
+func TestAddVariableFailureClampsCursor(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	esc := tea.KeyMsg{Type: tea.KeyEsc}
+
+	tests := []struct {
+		name  string
+		keys  []tea.KeyMsg
+		error bool
+	}{
+		{"empty name", []tea.KeyMsg{runes("a"), runes("=Any%"), enter}, true},
+		{"duplicate name", []tea.KeyMsg{runes("a"), runes("Category=Any%"), enter, runes("a"), runes("Category=100%"), enter}, true},
+		{"cancelled", []tea.KeyMsg{runes("a"), runes("Category"), esc}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newEditModel("Forest")
+			m.editPage = editPageRun
+			m = press(m, tt.keys...)
+
+			if m.editing {
+				t.Error("still adding the variable")
+			}
+			if last := m.runPageRows() - 1; m.editField != last {
+				t.Errorf("cursor on row %d, want the last row %d", m.editField, last)
+			}
+			if got := m.editError != ""; got != tt.error {
+				t.Errorf("error = %q, want one: %v", m.editError, tt.error)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -13,92 +14,233 @@ func (m model) View() string {
 	if m.mode == modeEditSplits {
 		return m.renderEditMode()
 	}
+	if m.mode == modeHistory {
+		return m.renderHistoryMode()
+	}
+	if m.mode == modeStats {
+		return m.renderStatsMode()
+	}
+
+	run := m.run.Snapshot()
+	config := m.run.UIConfig
+
+	sections := map[sugarSplitCore.UISection][]sugarSplitCore.UIComponentConfig{}
+	for _, p := range config.Placement() {
+		sections[p.Section] = append(sections[p.Section], p)
+	}
 
-	var top, middle, bottom strings.Builder
-	styles := initializeStyles(m.width)
+	top := "\n" + m.renderColumn(sections[sugarSplitCore.SectionTop], run, m.width, 0)
+	bottom := m.renderColumn(sections[sugarSplitCore.SectionBottom], run, m.width, 0)
 
-	top.WriteString("\n")
+	// The middle gets the lines the top and bottom leave free
+	available := max(m.height-strings.Count(top, "\n")-strings.Count(bottom, "\n"), 1)
 
-	// Define which components go where
-	topComponents := []sugarSplitCore.UIComponent{
-		sugarSplitCore.UIHeader,
+	left, right := sections[sugarSplitCore.SectionLeft], sections[sugarSplitCore.SectionRight]
+	columns := [][]sugarSplitCore.UIComponentConfig{}
+	for _, column := range [][]sugarSplitCore.UIComponentConfig{left, sections[sugarSplitCore.SectionMiddle], right} {
+		if len(column) > 0 {
+			columns = append(columns, column)
+		}
 	}
 
-	middleComponents := []sugarSplitCore.UIComponent{
-		sugarSplitCore.UISplits,
+	// Side by side columns need room for their content, otherwise left and
+	// right are stacked around the middle
+	var middle string
+	if len(columns) > 1 && m.width >= config.WideWidth && m.width/len(columns) >= minColumnWidth {
+		rendered := make([]string, len(columns))
+		for i, column := range columns {
+			width := m.width / len(columns)
+			if i == len(columns)-1 {
+				width = m.width - width*(len(columns)-1)
+			}
+			content := strings.TrimSuffix(m.renderColumn(column, run, width, available), "\n")
+			rendered[i] = lipgloss.NewStyle().Width(width).MaxWidth(width).Height(available).MaxHeight(available).Render(content)
+		}
+		middle = lipgloss.JoinHorizontal(lipgloss.Top, rendered...) + "\n"
+	} else {
+		var stacked []sugarSplitCore.UIComponentConfig
+		for _, column := range columns {
+			stacked = append(stacked, column...)
+		}
+		middle = m.renderColumn(stacked, run, m.width, available)
 	}
 
-	bottomComponents := []sugarSplitCore.UIComponent{
-		sugarSplitCore.UITimer,
-		sugarSplitCore.UIPreviousSegment,
-		sugarSplitCore.UIControls,
+	// Combine all sections
+	return top + middle + bottom
+}
+
+// minColumnWidth is the narrowest a column can be without cutting off lines
+const minColumnWidth = 40
+
+// renderComponent draws one component of the layout. The split list is
+// limited to the given number of lines, 0 shows every split.
+func (m model) renderComponent(component sugarSplitCore.UIComponentConfig, run sugarSplitCore.RunSnapshot, lines int) string {
+	styles := m.styles()
+
+	switch component.Component {
+	case sugarSplitCore.UIHeader:
+		return m.renderHeader(styles, run)
+	case sugarSplitCore.UISplits:
+		return m.renderSplits(styles, run, lines)
+	case sugarSplitCore.UITimer:
+		return m.renderTimer(styles, run)
+	case sugarSplitCore.UIPreviousSegment:
+		return m.renderPreviousSegment(styles, run, component)
+	case sugarSplitCore.UIControls:
+		return m.renderControls(styles, run)
+	case sugarSplitCore.UIPBChance:
+		return m.renderPBChance(styles, run)
+	case sugarSplitCore.UIGraph:
+		return m.renderGraph(styles, run)
+	case sugarSplitCore.UIDetailedTimer:
+		return m.renderDetailedTimer(styles, run)
+	case sugarSplitCore.UITitle:
+		return m.renderTitle(styles, run)
+	case sugarSplitCore.UIClock:
+		return m.renderClock(styles, component)
+	case sugarSplitCore.UISessionTimer:
+		return m.renderSessionTimer(styles, component)
+	case sugarSplitCore.UIText:
+		return m.renderText(styles, run, component)
+	case sugarSplitCore.UIBlank:
+		return strings.Repeat("\n", max(component.Lines, 1))
+	case sugarSplitCore.UISeparator:
+		return m.renderSeparator(styles)
+	case sugarSplitCore.UISumOfBest:
+		return m.renderSumOfBest(styles, run)
+	case sugarSplitCore.UIPossibleTimeSave:
+		return m.renderPossibleTimeSave(styles, run)
 	}
+	return ""
+}
 
-	// Prepare all possible components
-	components := map[sugarSplitCore.UIComponent]func() string{
-		sugarSplitCore.UIHeader:          func() string { return m.renderHeader(styles) },
-		sugarSplitCore.UISplits:          func() string { return m.renderSplits(styles) },
-		sugarSplitCore.UITimer:           func() string { return m.renderTimer(styles) },
-		sugarSplitCore.UIPreviousSegment: func() string { return m.renderPreviousSegment(styles) },
-		sugarSplitCore.UIControls:        func() string { return m.renderControls(styles) },
+// renderColumn draws components below each other in the given width. With a
+// height the split list gets the lines the other components leave free and
+// the column is padded to fill it.
+func (m model) renderColumn(components []sugarSplitCore.UIComponentConfig, run sugarSplitCore.RunSnapshot, width, height int) string {
+	m.width = width
+
+	rendered := make([]string, len(components))
+	used := 0
+	for i, component := range components {
+		if component.Component != sugarSplitCore.UISplits {
+			rendered[i] = m.renderComponent(component, run, 0)
+			used += strings.Count(rendered[i], "\n")
+		}
 	}
 
-	// Helper function to render components in order
-	renderComponents := func(componentList []sugarSplitCore.UIComponent, builder *strings.Builder) {
-		for _, component := range m.run.UIConfig.Layout {
-			if contains(componentList, component) {
-				if renderFunc, exists := components[component]; exists {
-					builder.WriteString(renderFunc())
-				}
-			}
+	splitLines := 0
+	if height > 0 {
+		splitLines = max(height-used, 1)
+	}
+	for i, component := range components {
+		if component.Component == sugarSplitCore.UISplits {
+			rendered[i] = m.renderComponent(component, run, splitLines)
 		}
 	}
 
-	// Render each section
-	renderComponents(topComponents, &top)
-	renderComponents(middleComponents, &middle)
-	renderComponents(bottomComponents, &bottom)
+	column := strings.Join(rendered, "")
+	if lines := strings.Count(column, "\n"); lines < height {
+		column += strings.Repeat("\n", height-lines)
+	}
+	return column
+}
 
-	// Calculate available space
-	topHeight := strings.Count(top.String(), "\n")
-	bottomHeight := strings.Count(bottom.String(), "\n")
-	availableHeight := m.height - topHeight - bottomHeight
+// listWindow returns the range of a list of total rows to show in the given
+// number of lines so that the cursor stays roughly centered
+func listWindow(total, cursor, lines int) (int, int) {
+	if lines <= 0 || total <= lines {
+		return 0, total
+	}
 
-	// If we have middle content, make it fill available space
-	middleContent := middle.String()
-	if middleContent != "" {
-		currentMiddleHeight := strings.Count(middleContent, "\n")
-		if currentMiddleHeight < availableHeight {
-			padding := availableHeight - currentMiddleHeight
-			middle.WriteString(strings.Repeat("\n", padding))
-		}
-	} else {
-		// If no middle content, add padding between top and bottom
-		middle.WriteString(strings.Repeat("\n", availableHeight))
+	start := cursor - lines/2
+	if start < 0 {
+		start = 0
 	}
+	if start+lines > total {
+		start = total - lines
+	}
+	return start, start + lines
+}
 
-	// Combine all sections
-	return top.String() + middle.String() + bottom.String()
+// scrollOffset returns the first row to show of a list of total rows in the
+// given number of lines. The offset only moves when the cursor would leave
+// the view, so the list doesn't jump around while moving within it.
+func scrollOffset(offset, cursor, total, lines int) int {
+	if lines <= 0 || total <= lines {
+		return 0
+	}
+
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+lines {
+		offset = cursor - lines + 1
+	}
+	return min(max(offset, 0), total-lines)
 }
 
-func contains(slice []sugarSplitCore.UIComponent, item sugarSplitCore.UIComponent) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+// splitWindow returns the indexes of the splits to show in the given number
+// of lines. The list scrolls to keep upcoming splits after the current one
+// in view, and with pinLast the final split always takes the last line.
+func splitWindow(total, current, lines, upcoming int, pinLast bool) []int {
+	lines = max(lines, 1)
+	if total <= lines {
+		lines = total
 	}
-	return false
+
+	scrolling := total
+	if pinLast && lines > 1 && lines < total {
+		scrolling--
+		lines--
+	}
+
+	end := min(max(current+upcoming+1, lines), scrolling)
+	start := max(end-lines, 0)
+
+	indexes := make([]int, 0, lines+1)
+	for i := start; i < end; i++ {
+		indexes = append(indexes, i)
+	}
+	if scrolling < total {
+		indexes = append(indexes, total-1)
+	}
+	return indexes
+}
+
+// truncate shortens s to at most width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// fitWidth truncates s to width cells and pads it with spaces to fill them,
+// so columns line up with names in any script
+func fitWidth(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
 }
 
-func (m model) renderHeader(styles Styles) string {
+func (m model) renderHeader(styles Styles, run sugarSplitCore.RunSnapshot) string {
 	var s strings.Builder
+	game := styles.title.Render(run.State.GameName)
+	if icon, ok := m.icons.icon(run.State.GameIcon); ok {
+		game = icon + " " + game
+	}
 	headerSection := lipgloss.JoinVertical(lipgloss.Center,
-		styles.title.Render(m.run.State.GameName),
-		styles.title.Render(m.run.State.CategoryName),
+		game,
+		styles.title.Render(run.State.CategoryName),
 	)
 
-	if len(m.run.State.Segments.Segments) > 0 {
-		sumOfBest := sugarSplitCore.GetSumOfBest(m.run.State.Segments.Segments)
+	if len(run.State.Segments.Segments) > 0 {
+		sumOfBest := sugarSplitCore.GetSumOfBest(run.State.Segments.Segments)
 		if sumOfBest > 0 {
 			headerSection = lipgloss.JoinVertical(lipgloss.Center,
 				headerSection,
@@ -112,81 +254,116 @@ func (m model) renderHeader(styles Styles) string {
 	return s.String()
 }
 
-func (m model) renderSplits(styles Styles) string {
+// renderSplits draws the split list in at most the given number of lines,
+// or the configured number of splits. With 0 lines every split is shown.
+func (m model) renderSplits(styles Styles, run sugarSplitCore.RunSnapshot, lines int) string {
 	var s strings.Builder
 
-	// Calculate space for splits section
-	reservedSpace := 8 // Adjust based on your needs
-	maxSplits := m.height - reservedSpace
-
-	// Segments section (scrolling if needed)
-	visibleSplits := len(m.run.State.Segments.Segments)
-	if visibleSplits > maxSplits {
-		// If we have more splits than space, show a window around the current split
-		windowSize := maxSplits / 2
-		startIdx := m.run.CurrentSplit - windowSize
-		if startIdx < 0 {
-			startIdx = 0
-		}
-		endIdx := startIdx + maxSplits
-		if endIdx > len(m.run.State.Segments.Segments) {
-			endIdx = len(m.run.State.Segments.Segments)
-			startIdx = endIdx - maxSplits
-			if startIdx < 0 {
-				startIdx = 0
-			}
-		}
-		visibleSplits = endIdx - startIdx
+	config := m.run.UIConfig.Splits
+	if config.Visible > 0 {
+		lines = config.Visible
 	}
+	if lines <= 0 {
+		lines = len(run.State.Segments.Segments)
+	}
+	window := splitWindow(len(run.State.Segments.Segments), run.CurrentSplit, lines, max(config.Upcoming, 0), config.PinLast)
 
 	// Splits rendering
-	for i, segment := range m.run.State.Segments.Segments {
-		var segmentText string
-
-		// Handle empty SplitTimes
-		var pbTime = sugarSplitCore.ParseTime("")
-		var pbTimeStr = "-"
-		if len(segment.SplitTimes.SplitTime) > 0 && segment.SplitTimes.SplitTime[0].RealTime != "" {
-			pbTime = sugarSplitCore.ParseTime(segment.SplitTimes.SplitTime[0].RealTime)
-			pbTimeStr = sugarSplitCore.FormatDuration(pbTime)
-		}
-
-		// Left-aligned name, right-aligned times
-		if i < m.run.CurrentSplit {
-			var splitTime string
-			if m.run.Splits[i] == 0 {
-				splitTime = "-" // Show dash for skipped splits
-			} else {
-				splitTime = sugarSplitCore.FormatDuration(m.run.Splits[i])
-			}
+	columns := config.Columns
+	segments := run.State.Segments.Segments
+	cells := make([][]string, len(window))
+	widths := make([]int, len(columns))
+	for c, column := range columns {
+		widths[c] = column.Width
+	}
 
-			var diffText string
-			if m.run.Splits[i] == 0 {
-				diffText = "-"
-			} else {
-				diff := m.run.Comparison[i]
-				if diff < 0 {
-					diffText = styles.ahead.Render(fmt.Sprintf("-%v", sugarSplitCore.FormatDuration(-diff)))
-				} else {
-					diffText = styles.behind.Render(fmt.Sprintf("+%v", sugarSplitCore.FormatDuration(diff)))
+	for row, i := range window {
+		current := i == run.CurrentSplit
+		gold := i < run.CurrentSplit && run.IsGold[i]
+
+		cells[row] = make([]string, len(columns))
+		for c, column := range columns {
+			value := run.SplitColumn(column, i)
+			isDelta := column.Type == sugarSplitCore.ColumnDelta || column.Type == sugarSplitCore.ColumnSegmentDelta
+
+			var text string
+			var style lipgloss.Style
+			switch {
+			case !value.Valid && isDelta && i >= run.CurrentSplit:
+				text = ""
+			case !value.Valid:
+				text = "-" // Skipped splits and missing times
+			case value.Delta:
+				text = formatDelta(value.Time)
+				style = styles.delta(value.State)
+			default:
+				text = sugarSplitCore.FormatDuration(value.Time)
+				if value.Comparison && !current {
+					style = styles.muted
 				}
 			}
+			if gold && !value.Delta {
+				style = styles.gold
+			}
+			if current {
+				style = style.Background(styles.colors.highlight)
+			}
+
+			widths[c] = max(widths[c], lipgloss.Width(text))
+			cells[row][c] = style.Render(text)
+		}
+	}
 
-			nameWidth := m.width - 32 // Adjust based on your time format width
-			segmentText = fmt.Sprintf("%-*s %15s %15s", nameWidth, segment.Name, splitTime, diffText)
+	// Left-aligned name, right-aligned times
+	nameWidth := max(m.width, 40) - 2
+	for _, width := range widths {
+		nameWidth -= width + 1
+	}
+	// Names move right to make room for icons when any segment has one
+	withIcons := m.icons.hasSegmentIcons(segments)
+	if withIcons {
+		nameWidth -= iconWidth + 1
+	}
+	nameWidth = max(nameWidth, 1)
+
+	for row, i := range window {
+		segment := segments[i]
+
+		// Styled cells end with a reset, so the current row's background
+		// has to be set on every part of it
+		base := lipgloss.NewStyle()
+		if i == run.CurrentSplit {
+			base = base.Foreground(styles.colors.text).Background(styles.colors.highlight)
+		}
+
+		name := truncate(segment.Name, nameWidth)
+		padding := nameWidth - lipgloss.Width(name)
+		if i < run.CurrentSplit && run.IsGold[i] {
+			name = styles.gold.Render(name)
+		} else {
+			name = base.Render(name)
+		}
 
-			if m.run.IsGold[i] {
-				segmentText = styles.gold.Render(segmentText)
+		var line strings.Builder
+		if withIcons {
+			icon, ok := m.icons.icon(segment.Icon)
+			if !ok {
+				icon = strings.Repeat(" ", iconWidth)
 			}
-			s.WriteString(styles.segment.Render(segmentText))
-		} else if i == m.run.CurrentSplit {
-			nameWidth := m.width - 16
-			segmentText = fmt.Sprintf("%-*s %15s", nameWidth, segment.Name, pbTimeStr)
-			s.WriteString(styles.currentSegment.Render(segmentText))
+			line.WriteString(icon)
+			line.WriteString(base.Render(" "))
+		}
+		line.WriteString(name)
+		line.WriteString(base.Render(strings.Repeat(" ", padding)))
+		for c, cell := range cells[row] {
+			line.WriteString(base.Render(strings.Repeat(" ", widths[c]-lipgloss.Width(cell)+1)))
+			line.WriteString(cell)
+		}
+
+		if i == run.CurrentSplit {
+			s.WriteString(styles.currentSegment.Render(line.String()))
 		} else {
-			nameWidth := m.width - 16
-			segmentText = fmt.Sprintf("%-*s %15s", nameWidth, segment.Name, styles.pb.Render(pbTimeStr))
-			s.WriteString(styles.segment.Render(segmentText))
+			s.WriteString(styles.segment.Render(line.String()))
 		}
 		s.WriteString("\n")
 	}
@@ -194,22 +371,18 @@ func (m model) renderSplits(styles Styles) string {
 	return s.String()
 }
 
-func (m model) renderTimer(styles Styles) string {
+func (m model) renderTimer(styles Styles, run sugarSplitCore.RunSnapshot) string {
 	var s strings.Builder
 
 	timerStyle := styles.timer
 
-	if m.run.Completed {
-		timerStyle = timerStyle.Foreground(ColorPrimary)
-	} else if m.run.CurrentSplit > 0 && m.run.Comparison[m.run.CurrentSplit-1] < 0 {
-		timerStyle = timerStyle.Foreground(ColorAhead)
-	} else if m.run.CurrentSplit > 0 {
-		timerStyle = timerStyle.Foreground(ColorBehind)
+	if run.Completed && run.IsPB() {
+		timerStyle = timerStyle.Foreground(styles.colors.pb)
 	} else {
-		timerStyle = timerStyle.Foreground(ColorPrimary)
+		timerStyle = timerStyle.Foreground(styles.deltaColor(run.TimerDeltaState(), styles.colors.title))
 	}
 
-	bigTimer := getBigTimer(m.run.CurrentTime)
+	bigTimer := getBigTimer(run.CurrentTime, m.run.UIConfig.Timer, m.width)
 	for _, line := range bigTimer {
 		s.WriteString(timerStyle.Render(line))
 		s.WriteString("\n")
@@ -218,25 +391,93 @@ func (m model) renderTimer(styles Styles) string {
 	return s.String()
 }
 
-func (m model) renderPreviousSegment(styles Styles) string {
+// renderDetailedTimer shows the total time along with the running time of the
+// current segment and what the comparison and gold took for it
+func (m model) renderDetailedTimer(styles Styles, run sugarSplitCore.RunSnapshot) string {
 	var s strings.Builder
+	config := m.run.UIConfig.DetailedTimer
 
-	if m.run.CurrentSplit > 0 {
-		prevIndex := m.run.CurrentSplit - 1
-		segmentTime := m.run.GetSegmentTime(prevIndex)
-		pbSegmentTime := m.run.GetPBSegmentTime(prevIndex)
+	s.WriteString(m.renderTimer(styles, run))
+
+	index := run.CurrentSplit
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(run.State.Segments.Segments) {
+		return s.String()
+	}
+
+	if config.ShowSegmentTimer {
+		// The current segment started at the last split that wasn't skipped
+		var segmentStart time.Duration
+		for i := run.CurrentSplit - 1; i >= 0; i-- {
+			if run.Splits[i] > 0 {
+				segmentStart = run.Splits[i]
+				break
+			}
+		}
+		segmentTime := run.CurrentTime - segmentStart
+		if !run.Started {
+			segmentTime = 0
+		}
+		s.WriteString(styles.segment.Render(fmt.Sprintf("Segment: %s", sugarSplitCore.FormatDuration(segmentTime))))
+		s.WriteString("\n")
+	}
 
-		if segmentTime > 0 && pbSegmentTime > 0 {
-			diff := segmentTime - pbSegmentTime
+	var details []string
+	if config.ShowComparison {
+		comparison := "-"
+		current := run.ComparisonTime(index)
+		previous := time.Duration(0)
+		if index > 0 {
+			previous = run.ComparisonTime(index - 1)
+		}
+		if current > 0 && (index == 0 || previous > 0) {
+			comparison = sugarSplitCore.FormatDuration(current - previous)
+		}
+		details = append(details, fmt.Sprintf("%s: %s", run.ComparisonName, comparison))
+	}
+	if config.ShowGold {
+		gold := "-"
+		if best := sugarSplitCore.ParseTime(run.State.Segments.Segments[index].BestSegmentTime.RealTime); best > 0 {
+			gold = styles.gold.Render(sugarSplitCore.FormatDuration(best))
+		}
+		details = append(details, fmt.Sprintf("Best: %s", gold))
+	}
+	if len(details) > 0 {
+		s.WriteString(styles.segment.Render(strings.Join(details, "   ")))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+func (m model) renderPreviousSegment(styles Styles, run sugarSplitCore.RunSnapshot, config sugarSplitCore.UIComponentConfig) string {
+	var s strings.Builder
+
+	if run.CurrentSplit > 0 {
+		prevIndex := run.CurrentSplit - 1
+
+		if diff, ok := run.PreviousSegmentDelta(); ok {
 			var diffText string
 
 			if diff < 0 {
-				diffText = styles.ahead.Render(fmt.Sprintf("-%v", sugarSplitCore.FormatDuration(-diff)))
+				diffText = styles.ahead.Render(formatDelta(diff))
 			} else {
-				diffText = styles.behind.Render(fmt.Sprintf("+%v", sugarSplitCore.FormatDuration(diff)))
+				diffText = styles.behind.Render(formatDelta(diff))
 			}
 
-			s.WriteString(styles.segment.Render(fmt.Sprintf("Previous Segment: %s", diffText)))
+			line := fmt.Sprintf("Previous Segment: %s", diffText)
+			if config.ShowPossibleTimeSave {
+				timeSave := "-"
+				column := sugarSplitCore.SplitColumnConfig{Type: sugarSplitCore.ColumnPossibleTimeSave}
+				if value := run.SplitColumn(column, prevIndex); value.Valid {
+					timeSave = sugarSplitCore.FormatDuration(value.Time)
+				}
+				line += fmt.Sprintf("   Possible Time Save: %s", timeSave)
+			}
+
+			s.WriteString(styles.segment.Render(line))
 			s.WriteString("\n")
 		}
 	}
@@ -244,55 +485,91 @@ func (m model) renderPreviousSegment(styles Styles) string {
 	return s.String()
 }
 
-func (m model) renderControls(styles Styles) string {
+func (m model) renderPBChance(styles Styles, run sugarSplitCore.RunSnapshot) string {
+	var s strings.Builder
+
+	var prediction sugarSplitCore.Prediction
+	if m.predictor != nil && m.predictor.State() == run.State && !run.Completed {
+		// The current segment started at the last split that wasn't skipped
+		var lastSplit time.Duration
+		for i := run.CurrentSplit - 1; i >= 0; i-- {
+			if run.Splits[i] > 0 {
+				lastSplit = run.Splits[i]
+				break
+			}
+		}
+		prediction = m.predictor.Predict(run.CurrentSplit, lastSplit, run.CurrentTime)
+	}
+
+	if prediction.Samples == 0 {
+		s.WriteString(styles.segment.Render("PB Chance: -"))
+	} else {
+		s.WriteString(styles.segment.Render(fmt.Sprintf("PB Chance: %.1f%%   Predicted: %s",
+			prediction.PBChance*100, sugarSplitCore.FormatDuration(prediction.ExpectedFinal))))
+	}
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+func (m model) renderControls(styles Styles, run sugarSplitCore.RunSnapshot) string {
 	var s strings.Builder
 
 	// Controls
-	s.WriteString(styles.controls.Render(m.run.GetAvailableHotkeys()))
+	s.WriteString(styles.controls.Render(run.GetAvailableHotkeys()))
 
 	return s.String()
 }
 
 func (m model) renderEditMode() string {
 	var s strings.Builder
-	styles := initializeStyles(m.width)
+	styles := m.styles()
+
+	// Page tabs, the shown page highlighted
+	splitsTab, runTab := styles.muted.Render("Splits"), styles.muted.Render("Run")
+	if m.editPage == editPageSplits {
+		splitsTab = styles.title.UnsetWidth().Render("Splits")
+	} else {
+		runTab = styles.title.UnsetWidth().Render("Run")
+	}
 
 	s.WriteString("\n")
-	s.WriteString(styles.title.Render("Edit Splits"))
+	s.WriteString(styles.segment.Render(splitsTab + "  " + runTab + styles.muted.Render("  (tab)")))
 	s.WriteString("\n")
-	s.WriteString(styles.title.Render(m.run.State.GameName + " - " + m.run.State.CategoryName))
+	s.WriteString(styles.title.Render(m.editState.GameName + " - " + m.editState.CategoryName))
 	s.WriteString("\n\n")
 
-	// Render splits with selection
-	for i, segment := range m.run.State.Segments.Segments {
-		var line string
-		if i == m.editIndex {
-			if m.editing {
-				// Show text input
-				line = fmt.Sprintf("> %s█", m.editInput)
-			} else {
-				line = fmt.Sprintf("> %s", segment.Name)
-			}
-			s.WriteString(styles.currentSegment.Render(line))
-		} else {
-			line = fmt.Sprintf("  %s", segment.Name)
-			s.WriteString(styles.segment.Render(line))
-		}
+	var lines []string
+	if m.editPage == editPageRun {
+		lines = m.renderRunPage(styles)
+	} else {
+		lines = m.renderSplitsPage(styles)
+	}
+	for _, line := range lines {
+		s.WriteString(line)
 		s.WriteString("\n")
 	}
 
 	// Calculate padding to push controls to bottom
-	contentHeight := 5 + len(m.run.State.Segments.Segments) + 4 // header + splits + controls
+	contentHeight := 5 + len(lines) + 4 // header + rows + controls
 	if m.height > contentHeight {
 		s.WriteString(strings.Repeat("\n", m.height-contentHeight))
 	}
 
-	// Controls help
+	// Controls help, or what's wrong with the input
 	s.WriteString("\n")
-	if m.editing {
+	switch {
+	case m.editError != "":
+		s.WriteString(styles.controls.Render(styles.behind.Render(m.editError)))
+	case m.editing && m.editColumn == editColumnImport:
+		prompt := "Split list file or pasted list: "
+		s.WriteString(styles.controls.Render(prompt + m.editInput.view(m.contentWidth()-lipgloss.Width(prompt))))
+	case m.editing:
 		s.WriteString(styles.controls.Render("Enter: Confirm | Esc: Cancel"))
-	} else {
-		s.WriteString(styles.controls.Render("j/k: Navigate | r: Rename | a: Add | d: Delete | J/K: Reorder"))
+	case m.editPage == editPageRun:
+		s.WriteString(styles.controls.Render("j/k: Navigate | r: Edit | a: Add variable | d: Delete variable"))
+	default:
+		s.WriteString(styles.controls.Render("r: Name | p/s/g: Times | a/d: Add/Del | m/c: Merge/Cut | J/K: Move | i: Import"))
 	}
 
 	// Bottom action buttons
@@ -303,3 +580,59 @@ func (m model) renderEditMode() string {
 
 	return s.String()
 }
+
+// editTimeWidth is the width of the time columns of the splits page
+const editTimeWidth = 11
+
+// renderSplitsPage lists the splits that fit with their PB and gold times,
+// the selected one highlighted with the text input while editing
+func (m model) renderSplitsPage(styles Styles) []string {
+	nameWidth := max(m.contentWidth()-2-3*(editTimeWidth+1), 1)
+	row := func(name string, times ...string) string {
+		line := truncate(name, nameWidth)
+		line += strings.Repeat(" ", nameWidth-lipgloss.Width(line))
+		for _, t := range times {
+			line += " " + lipgloss.PlaceHorizontal(editTimeWidth, lipgloss.Right, t)
+		}
+		return line
+	}
+
+	lines := []string{styles.segment.Render(styles.muted.Render("  " + row("", "PB Split", "PB Segment", "Gold")))}
+
+	segments := m.editState.Segments.Segments
+	height := m.editListHeight()
+	start := scrollOffset(m.editScroll, m.editIndex, len(segments), height)
+	end := min(start+max(height, 1), len(segments))
+	for i := start; i < end; i++ {
+		values := []string{
+			segments[i].Name,
+			editTime(m.editState.PBSplitTime(i)),
+			editTime(m.editState.PBSegmentTime(i)),
+			editTime(m.editState.GoldTime(i)),
+		}
+		if i != m.editIndex {
+			lines = append(lines, styles.segment.Render("  "+row(values[0], values[1:]...)))
+			continue
+		}
+
+		if m.editing && m.editColumn != editColumnImport {
+			// Show text input
+			width := editTimeWidth
+			if m.editColumn == editColumnName {
+				width = nameWidth
+			}
+			values[m.editColumn] = m.editInput.view(width)
+		}
+		lines = append(lines, styles.currentSegment.Render("> "+row(values[0], values[1:]...)))
+	}
+
+	return lines
+}
+
+// formatDelta formats a delta to a comparison with its sign
+func formatDelta(d time.Duration) string {
+	if d < 0 {
+		return "-" + sugarSplitCore.FormatDuration(-d)
+	}
+	return "+" + sugarSplitCore.FormatDuration(d)
+}
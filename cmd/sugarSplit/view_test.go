@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		current  int
+		lines    int
+		upcoming int
+		pinLast  bool
+		want     []int
+	}{
+		{"larger than the list", 5, 2, 10, 1, true, []int{0, 1, 2, 3, 4}},
+		{"not started", 10, -1, 4, 1, true, []int{0, 1, 2, 9}},
+		{"pinned last split", 10, 5, 4, 1, true, []int{4, 5, 6, 9}},
+		{"no upcoming splits", 10, 5, 4, 0, true, []int{3, 4, 5, 9}},
+		{"two upcoming splits", 10, 5, 4, 2, false, []int{4, 5, 6, 7}},
+		{"last split not pinned", 10, 5, 4, 1, false, []int{3, 4, 5, 6}},
+		{"pinned split reached", 10, 8, 4, 1, true, []int{6, 7, 8, 9}},
+		{"completed run", 10, 10, 4, 1, true, []int{6, 7, 8, 9}},
+		{"completed run not pinned", 10, 10, 4, 1, false, []int{6, 7, 8, 9}},
+		{"single line", 10, 5, 1, 1, true, []int{6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitWindow(tt.total, tt.current, tt.lines, tt.upcoming, tt.pinLast)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitWindow = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrollOffset(t *testing.T) {
+	tests := []struct {
+		name                         string
+		offset, cursor, total, lines int
+		want                         int
+	}{
+		{"cursor in view", 0, 3, 10, 5, 0},
+		{"cursor below the view", 0, 7, 10, 5, 3},
+		{"cursor above the view", 5, 3, 10, 5, 3},
+		{"list fits", 2, 2, 3, 5, 0},
+		{"offset past the end", 8, 9, 10, 5, 5},
+		{"no lines", 4, 4, 10, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrollOffset(tt.offset, tt.cursor, tt.total, tt.lines); got != tt.want {
+				t.Errorf("scrollOffset = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
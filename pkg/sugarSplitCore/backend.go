@@ -4,15 +4,20 @@ import (
 	"encoding/xml"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // XML structures
 type LiveSplitState struct {
 	XMLName              xml.Name       `xml:"Run"`
+	Version              string         `xml:"version,attr,omitempty"`
+	GameIcon             string         `xml:"GameIcon"`
 	GameName             string         `xml:"GameName"`
 	CategoryName         string         `xml:"CategoryName"`
+	LayoutPath           string         `xml:"LayoutPath,omitempty"`
 	Metadata             Metadata       `xml:"Metadata"`
 	Offset               string         `xml:"Offset"`
 	AttemptCount         int            `xml:"AttemptCount"`
@@ -22,14 +27,34 @@ type LiveSplitState struct {
 }
 
 type Metadata struct {
-	Run      MetadataRun `xml:"Run"`
-	Platform string      `xml:"Platform,omitempty"`
+	Run       MetadataRun       `xml:"Run"`
+	Platform  MetadataPlatform  `xml:"Platform"`
+	Region    string            `xml:"Region"`
+	Variables MetadataVariables `xml:"Variables"`
 }
 
 type MetadataRun struct {
+	// ID is the speedrun.com run the splits belong to
+	ID      string `xml:"id,attr"`
 	Version string `xml:"version,attr"`
 }
 
+type MetadataPlatform struct {
+	UsesEmulator bool   `xml:"usesEmulator,attr"`
+	Name         string `xml:",chardata"`
+}
+
+// MetadataVariables are the speedrun.com variables of the category, like
+// the difficulty or version
+type MetadataVariables struct {
+	Variable []MetadataVariable `xml:"Variable"`
+}
+
+type MetadataVariable struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
 type AttemptHistory struct {
 	Attempt []Attempt `xml:"Attempt"`
 }
@@ -40,8 +65,16 @@ type Attempt struct {
 	IsStartedSynced string `xml:"isStartedSynced,attr"`
 	Ended           string `xml:"ended,attr"`
 	IsEndedSynced   string `xml:"isEndedSynced,attr"`
+	RealTime        string `xml:"RealTime,omitempty"`
+	GameTime        string `xml:"GameTime,omitempty"`
 }
 
+// LSSVersion is the LiveSplit file version of the splits sugarSplit writes
+const LSSVersion = "1.7.0"
+
+// AttemptTimeLayout is the layout LiveSplit uses for attempt timestamps, in UTC
+const AttemptTimeLayout = "01/02/2006 15:04:05"
+
 type Segments struct {
 	Segments []Segment `xml:"Segment"`
 }
@@ -61,10 +94,12 @@ type SplitTimes struct {
 type SplitTime struct {
 	Name     string `xml:"name,attr"`
 	RealTime string `xml:"RealTime"`
+	GameTime string `xml:"GameTime,omitempty"`
 }
 
 type BestSegmentTime struct {
 	RealTime string `xml:"RealTime"`
+	GameTime string `xml:"GameTime,omitempty"`
 }
 
 type SegmentHistory struct {
@@ -73,28 +108,48 @@ type SegmentHistory struct {
 
 type Time struct {
 	ID       string `xml:"id,attr"`
-	RealTime string `xml:"RealTime"`
+	RealTime string `xml:"RealTime,omitempty"`
+	GameTime string `xml:"GameTime,omitempty"`
 }
 
-// Run represents the current state of a run
+// Run represents the current state of a run. It is safe for concurrent use:
+// frontends change it through its methods and read it through Snapshot.
 type Run struct {
-	State          *LiveSplitState
-	CurrentSplit   int
-	Splits         []time.Duration
-	IsGold         []bool
-	Comparison     []time.Duration
-	StartTime      time.Time
-	CurrentTime    time.Duration
-	Started        bool
-	Completed      bool
-	ResettingState bool
-	Hotkeys        []Hotkey
-	UIConfig       *UIConfig
-}
+	// UIConfig and Theme are loaded once in NewRun and never changed by the Run
+	UIConfig *UIConfig
+	Theme    *Theme
+
+	mu             sync.RWMutex
+	state          *LiveSplitState
+	currentSplit   int
+	splits         []time.Duration
+	isGold         []bool
+	comparison     []time.Duration
+	startTime      time.Time
+	attemptStarted time.Time
+	currentTime    time.Duration
+	started        bool
+	completed      bool
+	paused         bool
+	resetting      bool
+	comparisonName string
+	hotkeys        []Hotkey
+
+	subMu            sync.Mutex
+	subscribers      []subscriber
+	nextSubscriberID int
+}
+
+// Names of the comparisons every run provides
+const (
+	ComparisonPersonalBest = "Personal Best"
+	ComparisonBestSegments = "Best Segments"
+)
 
 // ### Core Splitter functions ###
 
-// NewRun creates a new Run instance from a LiveSplitState
+// NewRun creates a new Run instance from a LiveSplitState. The Run takes
+// ownership of state, so callers must not modify it afterwards.
 func NewRun(state *LiveSplitState, configPath string) (*Run, error) {
 	hotkeys, err := LoadHotkeys(configPath)
 	if err != nil {
@@ -106,160 +161,466 @@ func NewRun(state *LiveSplitState, configPath string) (*Run, error) {
 		return nil, fmt.Errorf("error loading UI config: %v", err)
 	}
 
+	theme, err := LoadTheme(configPath)
+	if err != nil {
+		return nil, err
+	}
+
 	run := &Run{
-		State:        state,
-		CurrentSplit: -1,
-		Splits:       make([]time.Duration, len(state.Segments.Segments)),
-		IsGold:       make([]bool, len(state.Segments.Segments)),
-		Comparison:   make([]time.Duration, len(state.Segments.Segments)),
-		Started:      false,
-		Completed:    false,
-		Hotkeys:      hotkeys,
-		UIConfig:     uiConfig,
-	}
-
-	run.UpdateHotkeyAvailability()
+		UIConfig:       uiConfig,
+		Theme:          theme,
+		state:          state,
+		comparisonName: ComparisonPersonalBest,
+		hotkeys:        append([]Hotkey(nil), hotkeys...),
+	}
+	run.clearLocked()
+
+	run.updateHotkeyAvailability()
 	return run, nil
 }
 
-// SaveRun saves the current run state to file
+// State returns the splits currently used by the run. The returned state is
+// shared and must be treated as read-only; use Clone to get a copy to edit.
+func (r *Run) State() *LiveSplitState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state
+}
+
+// SetState replaces the splits used by the run, e.g. after editing them, and
+// clears the current attempt. The Run takes ownership of state.
+func (r *Run) SetState(state *LiveSplitState) {
+	r.mu.Lock()
+	r.state = state
+	r.clearLocked()
+	r.updateHotkeyAvailability()
+	r.mu.Unlock()
+}
+
+// SaveRun records the current attempt in the splits and saves them to file.
+// The splits are only replaced once the file is written.
 func (r *Run) SaveRun(filename string) error {
+	r.mu.Lock()
+	state, pb := r.recordAttemptLocked()
+	if err := SaveRun(state, filename); err != nil {
+		r.mu.Unlock()
+		return err
+	}
+	r.state = state
+	r.mu.Unlock()
+
+	if pb > 0 {
+		r.emit(PersonalBestEvent{Time: pb})
+	}
+	return nil
+}
+
+// recordAttemptLocked returns a copy of the splits with the current attempt
+// added to the history, along with the final time if the attempt is a new PB
+func (r *Run) recordAttemptLocked() (*LiveSplitState, time.Duration) {
+	// The current state may be shared with snapshots, so work on a copy
+	state := r.state.Clone()
+
 	// Create new attempt
-	newAttemptID := len(r.State.AttemptHistory.Attempt) + 1
+	newAttemptID := fmt.Sprintf("%d", state.nextAttemptID())
+	started := r.attemptStarted
 	now := time.Now()
+	if started.IsZero() {
+		started = now
+	}
 
 	attempt := Attempt{
-		ID:              fmt.Sprintf("%d", newAttemptID),
-		Started:         now.Format("01/02/2006 15:04:05"),
+		ID:              newAttemptID,
+		Started:         started.UTC().Format(AttemptTimeLayout),
 		IsStartedSynced: "True",
-		Ended:           now.Format("01/02/2006 15:04:05"),
+		Ended:           now.UTC().Format(AttemptTimeLayout),
 		IsEndedSynced:   "True",
 	}
 
-	r.State.AttemptHistory.Attempt = append(r.State.AttemptHistory.Attempt, attempt)
-	r.State.AttemptCount++
+	snapshot := r.snapshotLocked()
+	isPB := snapshot.IsPB()
+	if r.completed && r.splits[len(r.splits)-1] > 0 {
+		attempt.RealTime = formatDurationLSS(r.splits[len(r.splits)-1])
+	}
+
+	state.AttemptHistory.Attempt = append(state.AttemptHistory.Attempt, attempt)
+	state.AttemptCount++
 
-	isPB := r.IsPB()
+	// Update segments up to the furthest split that was reached
+	for i := 0; i < r.currentSplit && i < len(r.splits); i++ {
+		split := r.splits[i]
+		segment := &state.Segments.Segments[i]
 
-	// Update segments
-	for i, split := range r.Splits {
+		// Add to segment history. Like LiveSplit, a skipped segment gets an
+		// empty entry and the next segment's time covers both.
+		newTime := Time{ID: newAttemptID}
 		if split > 0 {
-			// Add to segment history
-			newTime := Time{
-				ID:       fmt.Sprintf("%d", newAttemptID),
-				RealTime: formatDurationLSS(split),
+			newTime.RealTime = formatDurationLSS(segmentSince(r.splits, i))
+		}
+		segment.SegmentHistory.Time = append(segment.SegmentHistory.Time, newTime)
+
+		// Update best segment time if this was a gold split
+		if r.isGold[i] {
+			segment.BestSegmentTime.RealTime = formatDurationLSS(segmentSince(r.splits, i))
+		}
+
+		// Update PB split time if this is a PB run, leaving skipped splits empty
+		if isPB {
+			if len(segment.SplitTimes.SplitTime) == 0 {
+				segment.SplitTimes.SplitTime = append(
+					segment.SplitTimes.SplitTime,
+					SplitTime{Name: "Personal Best"},
+				)
 			}
-			r.State.Segments.Segments[i].SegmentHistory.Time = append(
-				r.State.Segments.Segments[i].SegmentHistory.Time,
-				newTime,
-			)
-
-			// Update best segment time if this was a gold split
-			if r.IsGold[i] {
-				var splitTime time.Duration
-				if i == 0 {
-					splitTime = split
-				} else {
-					splitTime = split - r.Splits[i-1]
-				}
-				r.State.Segments.Segments[i].BestSegmentTime.RealTime = formatDurationLSS(splitTime)
+			// Game time isn't measured, so an old PB's game time can't stay
+			segment.SplitTimes.SplitTime[0].RealTime = ""
+			segment.SplitTimes.SplitTime[0].GameTime = ""
+			if split > 0 {
+				segment.SplitTimes.SplitTime[0].RealTime = formatDurationLSS(split)
 			}
+		}
+	}
 
-			// Update PB split time if this is a PB run
-			if isPB {
-				if len(r.State.Segments.Segments[i].SplitTimes.SplitTime) == 0 {
-					r.State.Segments.Segments[i].SplitTimes.SplitTime = append(
-						r.State.Segments.Segments[i].SplitTimes.SplitTime,
-						SplitTime{Name: "Personal Best"},
-					)
-				}
-				r.State.Segments.Segments[i].SplitTimes.SplitTime[0].RealTime = formatDurationLSS(split)
-			}
+	if !isPB {
+		return state, 0
+	}
+	return state, r.splits[len(r.splits)-1]
+}
+
+// segmentSince returns the time between the split at index and the last split
+// before it that wasn't skipped
+func segmentSince(splits []time.Duration, index int) time.Duration {
+	for i := index - 1; i >= 0; i-- {
+		if splits[i] > 0 {
+			return splits[index] - splits[i]
 		}
 	}
+	return splits[index]
+}
 
-	return SaveRun(r.State, filename)
+// SaveAndReset saves the current attempt to file and resets the run
+func (r *Run) SaveAndReset(filename string) error {
+	err := r.SaveRun(filename)
+	r.reset(err == nil)
+	return err
 }
 
-// Split performs a split operation and updates relevant state
-func (r *Run) Split(currentTime time.Duration) {
-	if r.CurrentSplit >= len(r.State.Segments.Segments) {
+// Start starts the timer at the given wall clock time
+func (r *Run) Start(now time.Time) {
+	r.mu.Lock()
+	if r.started || r.completed || r.resetting {
+		r.mu.Unlock()
 		return
 	}
 
-	r.Splits[r.CurrentSplit] = currentTime
+	// A negative offset counts down to zero before the run begins
+	offset := ParseTime(r.state.Offset)
+	r.started = true
+	r.startTime = now.Add(-offset)
+	r.attemptStarted = now
+	r.currentTime = offset
+	r.currentSplit = 0
+	r.updateHotkeyAvailability()
+	r.mu.Unlock()
 
-	// Compare with PB and check for gold
-	pbTime := ParseTime(r.State.Segments.Segments[r.CurrentSplit].SplitTimes.SplitTime[0].RealTime)
-	goldTime := ParseTime(r.State.Segments.Segments[r.CurrentSplit].BestSegmentTime.RealTime)
+	r.emit(StartedEvent{StartTime: now})
+}
 
-	// Calculate split time (time since last split)
-	var splitTime time.Duration
-	if r.CurrentSplit == 0 {
-		splitTime = currentTime
-	} else {
-		splitTime = currentTime - r.Splits[r.CurrentSplit-1]
+// Tick updates the running time to the given wall clock time
+func (r *Run) Tick(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started && !r.resetting && !r.paused {
+		r.currentTime = now.Sub(r.startTime)
+	}
+}
+
+// Pause stops the timer without ending the run
+func (r *Run) Pause(now time.Time) {
+	r.mu.Lock()
+	if !r.started || r.completed || r.paused {
+		r.mu.Unlock()
+		return
+	}
+
+	r.currentTime = now.Sub(r.startTime)
+	r.paused = true
+	r.updateHotkeyAvailability()
+	pausedAt := r.currentTime
+	r.mu.Unlock()
+
+	r.emit(PausedEvent{Time: pausedAt})
+}
+
+// Resume continues a paused timer from where it stopped
+func (r *Run) Resume(now time.Time) {
+	r.mu.Lock()
+	if !r.paused {
+		r.mu.Unlock()
+		return
+	}
+
+	r.startTime = now.Add(-r.currentTime)
+	r.paused = false
+	r.updateHotkeyAvailability()
+	resumedAt := r.currentTime
+	r.mu.Unlock()
+
+	r.emit(ResumedEvent{Time: resumedAt})
+}
+
+// Split performs a split at the given wall clock time and updates relevant state
+func (r *Run) Split(now time.Time) {
+	r.mu.Lock()
+	if !r.started || r.completed || r.paused || r.resetting ||
+		r.currentSplit < 0 || r.currentSplit >= len(r.state.Segments.Segments) {
+		r.mu.Unlock()
+		return
+	}
+
+	// Splits can't happen before the offset has counted down
+	currentTime := now.Sub(r.startTime)
+	if currentTime <= 0 {
+		r.mu.Unlock()
+		return
 	}
+	r.currentTime = currentTime
+	r.splits[r.currentSplit] = currentTime
+
+	// Compare with the active comparison and check for gold
+	comparisonTime := r.comparisonTimeLocked(r.currentSplit)
+	goldTime := ParseTime(r.state.Segments.Segments[r.currentSplit].BestSegmentTime.RealTime)
+
+	// Calculate split time (time since last split). A segment following a
+	// skipped one spans several segments, so it can't be a gold.
+	splitTime := segmentSince(r.splits, r.currentSplit)
+	afterSkip := r.currentSplit > 0 && r.splits[r.currentSplit-1] == 0
 
 	// Check if this is a gold split
-	r.IsGold[r.CurrentSplit] = splitTime < goldTime || goldTime == 0
+	r.isGold[r.currentSplit] = !afterSkip && (splitTime < goldTime || goldTime == 0)
+
+	// Calculate delta to the comparison
+	r.comparison[r.currentSplit] = currentTime - comparisonTime
 
-	// Calculate comparison to PB
-	r.Comparison[r.CurrentSplit] = currentTime - pbTime
+	event := SplitEvent{
+		Index: r.currentSplit,
+		Time:  currentTime,
+		Delta: r.comparison[r.currentSplit],
+		Gold:  r.isGold[r.currentSplit],
+	}
 
-	r.CurrentSplit++
-	if r.CurrentSplit >= len(r.State.Segments.Segments) {
-		r.Started = false
-		r.Completed = true
+	r.currentSplit++
+	if r.currentSplit >= len(r.state.Segments.Segments) {
+		r.started = false
+		r.completed = true
 	}
+	r.updateHotkeyAvailability()
+	r.mu.Unlock()
+
+	r.emit(event)
 }
 
-// UndoSplit reverses the last split
-func (r *Run) UndoSplit() {
-	if r.CurrentSplit > 0 {
-		r.CurrentSplit--
-		r.Splits[r.CurrentSplit] = 0
-		r.Comparison[r.CurrentSplit] = 0
-		r.IsGold[r.CurrentSplit] = false
-		if r.Completed {
-			r.Completed = false
-			r.Started = true
-		}
+// UndoSplit reverses the last split. Undoing the final split of a finished
+// run continues the timer as if the run never ended.
+func (r *Run) UndoSplit(now time.Time) {
+	r.mu.Lock()
+	if r.currentSplit <= 0 || r.resetting || r.paused {
+		r.mu.Unlock()
+		return
+	}
+
+	r.currentSplit--
+	r.splits[r.currentSplit] = 0
+	r.comparison[r.currentSplit] = 0
+	r.isGold[r.currentSplit] = false
+	if r.completed {
+		r.completed = false
+		r.started = true
+		r.startTime = now.Add(-r.currentTime)
 	}
+	r.updateHotkeyAvailability()
+	index := r.currentSplit
+	r.mu.Unlock()
+
+	r.emit(UndoEvent{Index: index})
 }
 
-// Skip skips the current split
+// SkipSplit skips the current split
 func (r *Run) SkipSplit() {
-	if !r.Started || r.Completed || r.CurrentSplit >= len(r.State.Segments.Segments) {
+	r.mu.Lock()
+	if !r.started || r.completed || r.paused || r.resetting ||
+		r.currentSplit < 0 || r.currentSplit >= len(r.state.Segments.Segments) {
+		r.mu.Unlock()
 		return
 	}
 
 	// Set current split as skipped (we'll use 0 duration to indicate a skip)
-	r.Splits[r.CurrentSplit] = 0
-	r.Comparison[r.CurrentSplit] = 0
-	r.IsGold[r.CurrentSplit] = false
+	r.splits[r.currentSplit] = 0
+	r.comparison[r.currentSplit] = 0
+	r.isGold[r.currentSplit] = false
 
-	r.CurrentSplit++
-	if r.CurrentSplit >= len(r.State.Segments.Segments) {
-		r.Completed = true
-		r.Started = false
+	index := r.currentSplit
+	r.currentSplit++
+	if r.currentSplit >= len(r.state.Segments.Segments) {
+		r.completed = true
+		r.started = false
 	}
 
-	r.UpdateHotkeyAvailability()
+	r.updateHotkeyAvailability()
+	r.mu.Unlock()
+
+	r.emit(SkipEvent{Index: index})
 }
 
-// Reset resets the run state
+// BeginReset asks for confirmation before the run is reset
+func (r *Run) BeginReset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started || r.completed {
+		r.resetting = true
+		r.updateHotkeyAvailability()
+	}
+}
+
+// CancelReset leaves the reset confirmation and continues the run
+func (r *Run) CancelReset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resetting = false
+	r.updateHotkeyAvailability()
+}
+
+// Reset resets the run state without saving the attempt
 func (r *Run) Reset() {
-	r.Started = false
-	r.Completed = false
-	r.CurrentSplit = -1
-	r.CurrentTime = 0
-	r.StartTime = time.Time{}
-	r.Splits = make([]time.Duration, len(r.State.Segments.Segments))
-	r.Comparison = make([]time.Duration, len(r.State.Segments.Segments))
-	r.IsGold = make([]bool, len(r.State.Segments.Segments))
-	r.ResettingState = false
-	r.UpdateHotkeyAvailability()
+	r.reset(false)
+}
+
+func (r *Run) reset(saved bool) {
+	r.mu.Lock()
+	r.clearLocked()
+	r.updateHotkeyAvailability()
+	r.mu.Unlock()
+
+	r.emit(ResetEvent{Saved: saved})
+}
+
+// clearLocked clears the current attempt and sizes the per-split arrays to the segments
+func (r *Run) clearLocked() {
+	n := len(r.state.Segments.Segments)
+	r.started = false
+	r.completed = false
+	r.paused = false
+	r.resetting = false
+	r.currentSplit = -1
+	r.currentTime = 0
+	r.startTime = time.Time{}
+	r.attemptStarted = time.Time{}
+	r.splits = make([]time.Duration, n)
+	r.comparison = make([]time.Duration, n)
+	r.isGold = make([]bool, n)
+}
+
+// Comparisons returns the names of all comparisons available for this run
+func (r *Run) Comparisons() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return comparisonNames(r.state)
+}
+
+func comparisonNames(state *LiveSplitState) []string {
+	names := []string{ComparisonPersonalBest}
+	seen := map[string]bool{ComparisonPersonalBest: true}
+
+	for _, segment := range state.Segments.Segments {
+		for _, splitTime := range segment.SplitTimes.SplitTime {
+			if splitTime.Name != "" && !seen[splitTime.Name] {
+				names = append(names, splitTime.Name)
+				seen[splitTime.Name] = true
+			}
+		}
+	}
+
+	return append(names, ComparisonBestSegments)
+}
+
+// SetComparison switches the comparison that deltas are calculated against
+func (r *Run) SetComparison(name string) {
+	r.mu.Lock()
+	if !r.setComparisonLocked(name) {
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+
+	r.emit(ComparisonChangedEvent{Comparison: name})
+}
+
+// NextComparison switches to the comparison after the active one
+func (r *Run) NextComparison() {
+	r.mu.Lock()
+	names := comparisonNames(r.state)
+	next := names[0]
+	for i, name := range names {
+		if name == r.comparisonName {
+			next = names[(i+1)%len(names)]
+			break
+		}
+	}
+	if !r.setComparisonLocked(next) {
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+
+	r.emit(ComparisonChangedEvent{Comparison: next})
+}
+
+// setComparisonLocked changes the comparison and recalculates the deltas,
+// reporting whether anything changed
+func (r *Run) setComparisonLocked(name string) bool {
+	if name == r.comparisonName {
+		return false
+	}
+
+	r.comparisonName = name
+	for i := 0; i < r.currentSplit && i < len(r.splits); i++ {
+		if r.splits[i] > 0 {
+			r.comparison[i] = r.splits[i] - r.comparisonTimeLocked(i)
+		}
+	}
+	return true
+}
+
+func (r *Run) comparisonTimeLocked(splitIndex int) time.Duration {
+	return ComparisonSplitTime(r.state.Segments.Segments, splitIndex, r.comparisonName)
+}
+
+// Snapshot returns an immutable view of the run for rendering
+func (r *Run) Snapshot() RunSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.snapshotLocked()
+}
+
+func (r *Run) snapshotLocked() RunSnapshot {
+	return RunSnapshot{
+		State:          r.state,
+		CurrentSplit:   r.currentSplit,
+		Splits:         append([]time.Duration(nil), r.splits...),
+		IsGold:         append([]bool(nil), r.isGold...),
+		Comparison:     append([]time.Duration(nil), r.comparison...),
+		StartTime:      r.startTime,
+		CurrentTime:    r.currentTime,
+		Started:        r.started,
+		Completed:      r.completed,
+		Paused:         r.paused,
+		ResettingState: r.resetting,
+		ComparisonName: r.comparisonName,
+		Hotkeys:        append([]Hotkey(nil), r.hotkeys...),
+	}
 }
 
 // Helper functions
@@ -275,6 +636,10 @@ func LoadRun(filename string) (*LiveSplitState, error) {
 		return nil, fmt.Errorf("error parsing LSS file: %v", err)
 	}
 
+	if run.Version == "" {
+		run.migrateSplitHistory()
+		run.Version = LSSVersion
+	}
 	return &run, nil
 }
 
@@ -327,6 +692,10 @@ func ParseTime(timeStr string) time.Duration {
 		return 0
 	}
 
+	if strings.HasPrefix(timeStr, "-") {
+		return -ParseTime(timeStr[1:])
+	}
+
 	timeStr = strings.TrimPrefix(timeStr, "00:")
 
 	var hours, minutes, seconds int
@@ -352,90 +721,105 @@ func ParseTime(timeStr string) time.Duration {
 		time.Duration(fraction*float64(time.Second))
 }
 
-// GetSumOfBest returns the sum of best segment times
-func GetSumOfBest(segments []Segment) time.Duration {
-	var sum time.Duration
-	for _, segment := range segments {
-		if segment.BestSegmentTime.RealTime != "" {
-			bestSegTime := ParseTime(segment.BestSegmentTime.RealTime)
-			sum += bestSegTime
-		}
+// ParseDuration parses a time typed in by the user, [-][[h:]m:]s[.fraction].
+// Unlike ParseTime it rejects anything else.
+func ParseDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	negative := strings.HasPrefix(text, "-")
+	parts := strings.Split(strings.TrimPrefix(text, "-"), ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q (use [h:]m:s.fraction)", text)
 	}
-	return sum
-}
 
-// GetSegmentTime returns the duration of a specific segment
-func (r *Run) GetSegmentTime(splitIndex int) time.Duration {
-	if splitIndex < 0 || splitIndex >= len(r.Splits) {
-		return 0
-	}
+	var d time.Duration
+	for i, part := range parts {
+		last := i == len(parts)-1
+		whole, fraction, hasFraction := strings.Cut(part, ".")
+		if whole == "" || strings.Trim(whole, "0123456789") != "" ||
+			(hasFraction && (!last || fraction == "" || strings.Trim(fraction, "0123456789") != "")) {
+			return 0, fmt.Errorf("invalid time %q (use [h:]m:s.fraction)", text)
+		}
 
-	if r.Splits[splitIndex] == 0 {
-		return 0
+		n, err := strconv.Atoi(whole)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q: %v", text, err)
+		}
+		// Minutes and seconds after a larger unit stay below 60
+		if i > 0 && n >= 60 {
+			return 0, fmt.Errorf("invalid time %q: %d is out of range", text, n)
+		}
+		d = d*60 + time.Duration(n)*time.Second
+
+		if hasFraction {
+			fraction = (fraction + "000000000")[:9]
+			nanos, _ := strconv.Atoi(fraction)
+			d += time.Duration(nanos)
+		}
 	}
 
-	if splitIndex == 0 {
-		return r.Splits[0]
+	if negative {
+		d = -d
 	}
+	return d, nil
+}
 
-	return r.Splits[splitIndex] - r.Splits[splitIndex-1]
+// GetSumOfBest returns the sum of best segment times
+func GetSumOfBest(segments []Segment) time.Duration {
+	return sumOfBest(segments, TimingRealTime)
 }
 
-// GetPBSegmentTime returns the Personal Best duration for a specific segment
-func (r *Run) GetPBSegmentTime(splitIndex int) time.Duration {
-	if splitIndex < 0 || splitIndex >= len(r.State.Segments.Segments) {
-		return 0
+func sumOfBest(segments []Segment, method string) time.Duration {
+	var sum time.Duration
+	for _, segment := range segments {
+		sum += segment.BestSegmentTime.Time(method)
 	}
+	return sum
+}
 
-	segment := r.State.Segments.Segments[splitIndex]
-	if len(segment.SplitTimes.SplitTime) == 0 {
+// ComparisonSplitTime returns the split time of the named comparison at the given index.
+// Best Segments is calculated from the golds, every other comparison is read from the
+// segments' SplitTimes.
+func ComparisonSplitTime(segments []Segment, splitIndex int, comparison string) time.Duration {
+	return ComparisonSplitTimeFor(segments, splitIndex, comparison, TimingRealTime)
+}
+
+// ComparisonSplitTimeFor is ComparisonSplitTime for the given timing method
+func ComparisonSplitTimeFor(segments []Segment, splitIndex int, comparison, method string) time.Duration {
+	if splitIndex < 0 || splitIndex >= len(segments) {
 		return 0
 	}
 
-	pbTime := ParseTime(segment.SplitTimes.SplitTime[0].RealTime)
-
-	if splitIndex == 0 {
-		return pbTime
+	if comparison == ComparisonBestSegments {
+		return sumOfBest(segments[:splitIndex+1], method)
 	}
 
-	prevPBTime := ParseTime(r.State.Segments.Segments[splitIndex-1].SplitTimes.SplitTime[0].RealTime)
-	return pbTime - prevPBTime
-}
-
-// IsPB checks if the current run is a Personal Best
-func (r *Run) IsPB() bool {
-	if r.CurrentSplit != len(r.State.Segments.Segments) {
-		return false
+	for _, splitTime := range segments[splitIndex].SplitTimes.SplitTime {
+		if splitTime.Name == comparison {
+			return splitTime.Time(method)
+		}
 	}
-
-	lastSplitTime := r.Splits[len(r.Splits)-1]
-	currentPB := ParseTime(r.State.Segments.Segments[len(r.Splits)-1].SplitTimes.SplitTime[0].RealTime)
-
-	return lastSplitTime < currentPB || currentPB == 0
-}
-
-// ReinitializeArrays reinitializes the run arrays after segment changes
-func (r *Run) ReinitializeArrays() {
-	n := len(r.State.Segments.Segments)
-	r.Splits = make([]time.Duration, n)
-	r.IsGold = make([]bool, n)
-	r.Comparison = make([]time.Duration, n)
-	r.CurrentSplit = -1
+	return 0
 }
 
 // ### Segment manipulation methods ###
 
+// Clone returns a deep copy of the state
+func (state *LiveSplitState) Clone() *LiveSplitState {
+	clone := *state
+	clone.AttemptHistory.Attempt = append([]Attempt(nil), state.AttemptHistory.Attempt...)
+	clone.Metadata.Variables.Variable = append([]MetadataVariable(nil), state.Metadata.Variables.Variable...)
+	clone.Segments.Segments = make([]Segment, len(state.Segments.Segments))
+	for i, segment := range state.Segments.Segments {
+		segment.SplitTimes.SplitTime = append([]SplitTime(nil), segment.SplitTimes.SplitTime...)
+		segment.SegmentHistory.Time = append([]Time(nil), segment.SegmentHistory.Time...)
+		clone.Segments.Segments[i] = segment
+	}
+	return &clone
+}
+
 // AddSegment adds a new segment after the specified index
 func (state *LiveSplitState) AddSegment(index int, name string) {
-	newSegment := Segment{
-		Name: name,
-		Icon: "",
-		SplitTimes: SplitTimes{
-			SplitTime: []SplitTime{{Name: "Personal Best", RealTime: ""}},
-		},
-		BestSegmentTime: BestSegmentTime{RealTime: ""},
-		SegmentHistory:  SegmentHistory{Time: []Time{}},
-	}
+	newSegment := NewSegment(name)
 
 	segments := state.Segments.Segments
 	// Insert after index
@@ -478,9 +862,23 @@ func (state *LiveSplitState) MoveSegmentDown(index int) {
 	}
 }
 
+// NewSegment returns a segment without any times
+func NewSegment(name string) Segment {
+	return Segment{
+		Name: name,
+		Icon: "",
+		SplitTimes: SplitTimes{
+			SplitTime: []SplitTime{{Name: "Personal Best", RealTime: ""}},
+		},
+		BestSegmentTime: BestSegmentTime{RealTime: ""},
+		SegmentHistory:  SegmentHistory{Time: []Time{}},
+	}
+}
+
 // CreateBlankRun creates a new empty LiveSplit state
 func CreateBlankRun(gameName, categoryName string) *LiveSplitState {
 	return &LiveSplitState{
+		Version:      LSSVersion,
 		GameName:     gameName,
 		CategoryName: categoryName,
 		Metadata: Metadata{
@@ -492,17 +890,7 @@ func CreateBlankRun(gameName, categoryName string) *LiveSplitState {
 			Attempt: []Attempt{},
 		},
 		Segments: Segments{
-			Segments: []Segment{
-				{
-					Name: "Split 1",
-					Icon: "",
-					SplitTimes: SplitTimes{
-						SplitTime: []SplitTime{{Name: "Personal Best", RealTime: ""}},
-					},
-					BestSegmentTime: BestSegmentTime{RealTime: ""},
-					SegmentHistory:  SegmentHistory{Time: []Time{}},
-				},
-			},
+			Segments: []Segment{NewSegment("Split 1")},
 		},
 		AutoSplitterSettings: "",
 	}
@@ -0,0 +1,150 @@
+package sugarSplitCore
+
+import "time"
+
+// SplitColumnValue is what a split list column shows for one segment
+type SplitColumnValue struct {
+	Time time.Duration
+	// Valid is false when the column has nothing to show for the segment
+	Valid bool
+	// Delta marks times shown with a sign, colored by State
+	Delta bool
+	State DeltaState
+	// Comparison marks times taken from the comparison because the split
+	// hasn't happened yet
+	Comparison bool
+}
+
+// SplitColumn returns the value of a split list column for the segment at index
+func (s RunSnapshot) SplitColumn(column SplitColumnConfig, index int) SplitColumnValue {
+	comparison := column.Comparison
+	if comparison == "" {
+		comparison = s.ComparisonName
+	}
+	method := column.TimingMethod
+	if method == "" {
+		method = TimingRealTime
+	}
+
+	segments := s.State.Segments.Segments
+	if index < 0 || index >= len(segments) {
+		return SplitColumnValue{}
+	}
+	done := index < s.CurrentSplit
+	// Only real time is measured, so game time columns have no times of
+	// the running attempt
+	live := method == TimingRealTime
+
+	switch column.Type {
+	case ColumnSplitTime:
+		if !done {
+			return comparisonValue(ComparisonSplitTimeFor(segments, index, comparison, method))
+		}
+		if live && s.Splits[index] > 0 {
+			return SplitColumnValue{Time: s.Splits[index], Valid: true}
+		}
+
+	case ColumnSegmentTime:
+		if !done {
+			return comparisonValue(comparisonSegmentTime(segments, index, comparison, method))
+		}
+		if segment := s.GetSegmentTime(index); live && segment > 0 && (index == 0 || s.Splits[index-1] > 0) {
+			return SplitColumnValue{Time: segment, Valid: true}
+		}
+
+	case ColumnDelta:
+		if done {
+			if delta, ok := s.splitDelta(index, comparison, method); ok {
+				return deltaValue(delta, s.splitDeltaState(index, comparison, method))
+			}
+		} else if index == s.CurrentSplit {
+			if delta, ok := s.liveDelta(comparison, method); ok {
+				return deltaValue(delta, s.liveDeltaState(comparison, method))
+			}
+		}
+
+	case ColumnSegmentDelta:
+		if delta, ok := s.segmentDelta(index, comparison, method); ok {
+			return deltaValue(delta, NewDeltaState(delta, 0))
+		}
+
+	case ColumnComparisonTime:
+		value := comparisonValue(ComparisonSplitTimeFor(segments, index, comparison, method))
+		value.Comparison = !done
+		return value
+
+	case ColumnGold:
+		if gold := segments[index].BestSegmentTime.Time(method); gold > 0 {
+			return SplitColumnValue{Time: gold, Valid: true}
+		}
+
+	case ColumnPossibleTimeSave:
+		segment := comparisonSegmentTime(segments, index, comparison, method)
+		gold := segments[index].BestSegmentTime.Time(method)
+		if segment > 0 && gold > 0 && segment >= gold {
+			return SplitColumnValue{Time: segment - gold, Valid: true}
+		}
+	}
+
+	return SplitColumnValue{}
+}
+
+// segmentDelta returns the time gained or lost on a segment against the
+// comparison's segment. The running segment has a delta once it has taken
+// longer than the comparison's.
+func (s RunSnapshot) segmentDelta(index int, comparison, method string) (time.Duration, bool) {
+	if method != TimingRealTime || index > s.CurrentSplit || index >= len(s.Splits) {
+		return 0, false
+	}
+	if index > 0 && s.Splits[index-1] <= 0 {
+		return 0, false
+	}
+
+	compared := comparisonSegmentTime(s.State.Segments.Segments, index, comparison, method)
+	if compared <= 0 {
+		return 0, false
+	}
+
+	var start time.Duration
+	if index > 0 {
+		start = s.Splits[index-1]
+	}
+
+	if index < s.CurrentSplit {
+		if s.Splits[index] <= 0 {
+			return 0, false
+		}
+		return s.Splits[index] - start - compared, true
+	}
+
+	if !s.Started || s.Completed || s.CurrentTime-start <= compared {
+		return 0, false
+	}
+	return s.CurrentTime - start - compared, true
+}
+
+// comparisonSegmentTime returns how long the comparison took for a segment,
+// or 0 if it doesn't have times for both ends of it
+func comparisonSegmentTime(segments []Segment, index int, comparison, method string) time.Duration {
+	split := ComparisonSplitTimeFor(segments, index, comparison, method)
+	if split <= 0 {
+		return 0
+	}
+	if index == 0 {
+		return split
+	}
+
+	prev := ComparisonSplitTimeFor(segments, index-1, comparison, method)
+	if prev <= 0 || prev > split {
+		return 0
+	}
+	return split - prev
+}
+
+func comparisonValue(t time.Duration) SplitColumnValue {
+	return SplitColumnValue{Time: t, Valid: t > 0, Comparison: true}
+}
+
+func deltaValue(delta time.Duration, state DeltaState) SplitColumnValue {
+	return SplitColumnValue{Time: delta, Valid: true, Delta: true, State: state}
+}
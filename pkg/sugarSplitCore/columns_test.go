@@ -0,0 +1,87 @@
+package sugarSplitCore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSplitColumn(t *testing.T) {
+	// PB splits at 10s, 30s and 60s, game time PB at 9s, 27s and 50s and a
+	// "Target" comparison at 12s, 28s and 55s
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 18 * time.Second, 25 * time.Second},
+	)
+	for i, d := range []time.Duration{9, 27, 50} {
+		segment := &state.Segments.Segments[i]
+		segment.SplitTimes.SplitTime[0].GameTime = formatDurationLSS(d * time.Second)
+	}
+	for i, d := range []time.Duration{12, 28, 55} {
+		segment := &state.Segments.Segments[i]
+		segment.SplitTimes.SplitTime = append(segment.SplitTimes.SplitTime, SplitTime{Name: "Target", RealTime: formatDurationLSS(d * time.Second)})
+	}
+
+	run, err := NewRun(state, filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("NewRun: %v", err)
+	}
+	// The first split is done at 8s and the second is running at 35s
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(8 * time.Second))
+	run.Tick(start.Add(35 * time.Second))
+	snapshot := run.Snapshot()
+
+	value := func(d time.Duration) SplitColumnValue {
+		return SplitColumnValue{Time: d, Valid: true}
+	}
+	compared := func(d time.Duration) SplitColumnValue {
+		return SplitColumnValue{Time: d, Valid: true, Comparison: true}
+	}
+	delta := func(d time.Duration, state DeltaState) SplitColumnValue {
+		return SplitColumnValue{Time: d, Valid: true, Delta: true, State: state}
+	}
+
+	tests := []struct {
+		name   string
+		column SplitColumnConfig
+		index  int
+		want   SplitColumnValue
+	}{
+		{"split time of a done split", SplitColumnConfig{Type: ColumnSplitTime}, 0, value(8 * time.Second)},
+		{"split time of the running split", SplitColumnConfig{Type: ColumnSplitTime}, 1, compared(30 * time.Second)},
+		{"segment time of a done split", SplitColumnConfig{Type: ColumnSegmentTime}, 0, value(8 * time.Second)},
+		{"segment time of the running split", SplitColumnConfig{Type: ColumnSegmentTime}, 1, compared(20 * time.Second)},
+		{"delta of a done split", SplitColumnConfig{Type: ColumnDelta}, 0, delta(-2*time.Second, DeltaAheadGaining)},
+		{"live delta past the comparison", SplitColumnConfig{Type: ColumnDelta}, 1, delta(5*time.Second, DeltaBehindLosing)},
+		{"delta of a future split", SplitColumnConfig{Type: ColumnDelta}, 2, SplitColumnValue{}},
+		{"segment delta of a done split", SplitColumnConfig{Type: ColumnSegmentDelta}, 0, delta(-2*time.Second, DeltaAheadGaining)},
+		{"segment delta of the running split", SplitColumnConfig{Type: ColumnSegmentDelta}, 1, delta(7*time.Second, DeltaBehindLosing)},
+		{"segment delta of a future split", SplitColumnConfig{Type: ColumnSegmentDelta}, 2, SplitColumnValue{}},
+		{"comparison time of a done split", SplitColumnConfig{Type: ColumnComparisonTime}, 0, value(10 * time.Second)},
+		{"comparison time of a future split", SplitColumnConfig{Type: ColumnComparisonTime}, 2, compared(60 * time.Second)},
+		{"gold", SplitColumnConfig{Type: ColumnGold}, 1, value(18 * time.Second)},
+		{"possible time save", SplitColumnConfig{Type: ColumnPossibleTimeSave}, 2, value(5 * time.Second)},
+		{"possible time save of the first split", SplitColumnConfig{Type: ColumnPossibleTimeSave}, 0, value(time.Second)},
+
+		// Columns with their own comparison
+		{"split time against another comparison", SplitColumnConfig{Type: ColumnSplitTime, Comparison: "Target"}, 2, compared(55 * time.Second)},
+		{"delta against another comparison", SplitColumnConfig{Type: ColumnDelta, Comparison: "Target"}, 0, delta(-4*time.Second, DeltaAheadGaining)},
+		{"possible time save against another comparison", SplitColumnConfig{Type: ColumnPossibleTimeSave, Comparison: "Target"}, 1, SplitColumnValue{}},
+		{"delta against best segments", SplitColumnConfig{Type: ColumnDelta, Comparison: ComparisonBestSegments}, 0, delta(-time.Second, DeltaAheadGaining)},
+
+		// Game time columns only have comparison times
+		{"game time split of a future split", SplitColumnConfig{Type: ColumnSplitTime, TimingMethod: TimingGameTime}, 2, compared(50 * time.Second)},
+		{"game time split of a done split", SplitColumnConfig{Type: ColumnSplitTime, TimingMethod: TimingGameTime}, 0, SplitColumnValue{}},
+		{"game time delta", SplitColumnConfig{Type: ColumnDelta, TimingMethod: TimingGameTime}, 0, SplitColumnValue{}},
+		{"game time gold that isn't set", SplitColumnConfig{Type: ColumnGold, TimingMethod: TimingGameTime}, 0, SplitColumnValue{}},
+
+		{"index out of range", SplitColumnConfig{Type: ColumnSplitTime}, 3, SplitColumnValue{}},
+	}
+	for _, tt := range tests {
+		if got := snapshot.SplitColumn(tt.column, tt.index); got != tt.want {
+			t.Errorf("%s: SplitColumn = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
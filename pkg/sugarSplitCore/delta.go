@@ -0,0 +1,118 @@
+package sugarSplitCore
+
+import "time"
+
+// DeltaState says whether a run is ahead of or behind its comparison and
+// whether the last segment gained or lost time, like LiveSplit colors deltas
+type DeltaState int
+
+const (
+	// DeltaNone is used when there is nothing to compare against
+	DeltaNone DeltaState = iota
+	DeltaAheadGaining
+	DeltaAheadLosing
+	DeltaBehindGaining
+	DeltaBehindLosing
+)
+
+// NewDeltaState returns the state of a delta given the delta at the split
+// before it. A segment that lost no time counts as losing, so a tie with the
+// comparison is never shown as gaining.
+func NewDeltaState(delta, previousDelta time.Duration) DeltaState {
+	gaining := delta < previousDelta
+	switch {
+	case delta < 0 && gaining:
+		return DeltaAheadGaining
+	case delta < 0:
+		return DeltaAheadLosing
+	case gaining:
+		return DeltaBehindGaining
+	default:
+		return DeltaBehindLosing
+	}
+}
+
+// splitDelta returns the delta of a completed split to a comparison, and
+// false if the split was skipped or the comparison has no time for it.
+// Only real time is measured, so there are no game time deltas.
+func (s RunSnapshot) splitDelta(index int, comparison, method string) (time.Duration, bool) {
+	if method == TimingGameTime || index < 0 || index >= s.CurrentSplit || index >= len(s.Splits) || s.Splits[index] <= 0 {
+		return 0, false
+	}
+
+	comparisonTime := ComparisonSplitTimeFor(s.State.Segments.Segments, index, comparison, method)
+	if comparisonTime <= 0 {
+		return 0, false
+	}
+	return s.Splits[index] - comparisonTime, true
+}
+
+// previousDelta returns the delta at the last split before index that has
+// one, or 0 at the start of the run
+func (s RunSnapshot) previousDelta(index int, comparison, method string) time.Duration {
+	for i := index - 1; i >= 0; i-- {
+		if delta, ok := s.splitDelta(i, comparison, method); ok {
+			return delta
+		}
+	}
+	return 0
+}
+
+// SplitDeltaState returns the delta state of a completed split
+func (s RunSnapshot) SplitDeltaState(index int) DeltaState {
+	return s.splitDeltaState(index, s.ComparisonName, TimingRealTime)
+}
+
+func (s RunSnapshot) splitDeltaState(index int, comparison, method string) DeltaState {
+	delta, ok := s.splitDelta(index, comparison, method)
+	if !ok {
+		return DeltaNone
+	}
+	return NewDeltaState(delta, s.previousDelta(index, comparison, method))
+}
+
+// LiveDelta returns the running delta of the current split once the timer
+// has passed the comparison's split time, and false before that
+func (s RunSnapshot) LiveDelta() (time.Duration, bool) {
+	return s.liveDelta(s.ComparisonName, TimingRealTime)
+}
+
+func (s RunSnapshot) liveDelta(comparison, method string) (time.Duration, bool) {
+	if method == TimingGameTime || !s.Started || s.Completed || s.CurrentSplit >= len(s.Splits) {
+		return 0, false
+	}
+
+	comparisonTime := ComparisonSplitTimeFor(s.State.Segments.Segments, s.CurrentSplit, comparison, method)
+	if comparisonTime <= 0 || s.CurrentTime <= comparisonTime {
+		return 0, false
+	}
+	return s.CurrentTime - comparisonTime, true
+}
+
+// LiveDeltaState returns the delta state of the running split, or DeltaNone
+// while it hasn't passed the comparison
+func (s RunSnapshot) LiveDeltaState() DeltaState {
+	return s.liveDeltaState(s.ComparisonName, TimingRealTime)
+}
+
+func (s RunSnapshot) liveDeltaState(comparison, method string) DeltaState {
+	delta, ok := s.liveDelta(comparison, method)
+	if !ok {
+		return DeltaNone
+	}
+	return NewDeltaState(delta, s.previousDelta(s.CurrentSplit, comparison, method))
+}
+
+// TimerDeltaState returns the state the timer is colored by: the live delta
+// when there is one, otherwise the state of the last split with a delta
+func (s RunSnapshot) TimerDeltaState() DeltaState {
+	if state := s.LiveDeltaState(); state != DeltaNone {
+		return state
+	}
+	for i := s.CurrentSplit - 1; i >= 0; i-- {
+		if state := s.SplitDeltaState(i); state != DeltaNone {
+			return state
+		}
+	}
+	return DeltaNone
+}
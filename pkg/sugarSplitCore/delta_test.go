@@ -0,0 +1,166 @@
+package sugarSplitCore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewDeltaState(t *testing.T) {
+	tests := []struct {
+		name          string
+		delta         time.Duration
+		previousDelta time.Duration
+		want          DeltaState
+	}{
+		{"ahead and gaining", -3 * time.Second, -1 * time.Second, DeltaAheadGaining},
+		{"ahead and losing", -1 * time.Second, -3 * time.Second, DeltaAheadLosing},
+		{"behind and gaining", 1 * time.Second, 3 * time.Second, DeltaBehindGaining},
+		{"behind and losing", 3 * time.Second, 1 * time.Second, DeltaBehindLosing},
+		{"ahead after being behind", -1 * time.Second, 2 * time.Second, DeltaAheadGaining},
+		{"behind after being ahead", 1 * time.Second, -2 * time.Second, DeltaBehindLosing},
+		{"tie with the previous delta", -2 * time.Second, -2 * time.Second, DeltaAheadLosing},
+	}
+	for _, tt := range tests {
+		if got := NewDeltaState(tt.delta, tt.previousDelta); got != tt.want {
+			t.Errorf("%s: NewDeltaState(%v, %v) = %v, want %v", tt.name, tt.delta, tt.previousDelta, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotDeltaStates(t *testing.T) {
+	// PB splits at 10s, 30s and 60s
+	type step struct {
+		split bool
+		skip  bool
+		at    time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+		// split is the completed split to check, -1 checks the running one
+		split int
+		want  DeltaState
+		timer DeltaState
+	}{
+		{
+			name:  "first split ahead",
+			steps: []step{{split: true, at: 8 * time.Second}},
+			split: 0, want: DeltaAheadGaining, timer: DeltaAheadGaining,
+		},
+		{
+			name:  "first split behind",
+			steps: []step{{split: true, at: 12 * time.Second}},
+			split: 0, want: DeltaBehindLosing, timer: DeltaBehindLosing,
+		},
+		{
+			// The delta before the skipped split is the one compared with
+			name:  "after a skipped split",
+			steps: []step{{split: true, at: 8 * time.Second}, {skip: true}, {split: true, at: 59 * time.Second}},
+			split: 2, want: DeltaAheadLosing, timer: DeltaAheadLosing,
+		},
+		{
+			name:  "skipped split has no state",
+			steps: []step{{split: true, at: 8 * time.Second}, {skip: true}, {at: 35 * time.Second}},
+			split: 1, want: DeltaNone, timer: DeltaAheadGaining,
+		},
+		{
+			// Until the comparison's split time passes, the timer keeps the
+			// last split's state
+			name:  "running split before the comparison",
+			steps: []step{{split: true, at: 15 * time.Second}, {at: 25 * time.Second}},
+			split: -1, want: DeltaNone, timer: DeltaBehindLosing,
+		},
+		{
+			name:  "running split behind but gaining",
+			steps: []step{{split: true, at: 15 * time.Second}, {at: 33 * time.Second}},
+			split: -1, want: DeltaBehindGaining, timer: DeltaBehindGaining,
+		},
+		{
+			name:  "running split losing the lead",
+			steps: []step{{split: true, at: 8 * time.Second}, {at: 31 * time.Second}},
+			split: -1, want: DeltaBehindLosing, timer: DeltaBehindLosing,
+		},
+	}
+
+	for _, tt := range tests {
+		state := newTimedRun(
+			[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+			[]time.Duration{9 * time.Second, 18 * time.Second, 25 * time.Second},
+		)
+		run, err := NewRun(state, filepath.Join(t.TempDir(), "missing.toml"))
+		if err != nil {
+			t.Fatalf("NewRun: %v", err)
+		}
+
+		start := time.Now()
+		run.Start(start)
+		for _, s := range tt.steps {
+			switch {
+			case s.skip:
+				run.SkipSplit()
+			case s.split:
+				run.Split(start.Add(s.at))
+			default:
+				run.Tick(start.Add(s.at))
+			}
+		}
+
+		snapshot := run.Snapshot()
+		var got DeltaState
+		if tt.split < 0 {
+			got = snapshot.LiveDeltaState()
+		} else {
+			got = snapshot.SplitDeltaState(tt.split)
+		}
+		if got != tt.want {
+			t.Errorf("%s: state = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := snapshot.TimerDeltaState(); got != tt.timer {
+			t.Errorf("%s: timer state = %v, want %v", tt.name, got, tt.timer)
+		}
+	}
+}
+
+func TestPreviousSegmentDelta(t *testing.T) {
+	// PB splits at 10s, 30s and 60s, golds of 9s, 18s and 25s
+	tests := []struct {
+		name       string
+		splits     []time.Duration
+		comparison string
+		want       time.Duration
+		ok         bool
+	}{
+		{"no split yet", nil, ComparisonPersonalBest, 0, false},
+		{"first split", []time.Duration{8 * time.Second}, ComparisonPersonalBest, -2 * time.Second, true},
+		{"second split", []time.Duration{8 * time.Second, 35 * time.Second}, ComparisonPersonalBest, 7 * time.Second, true},
+		// 51s since the split before the skipped one, against the PB's 50s
+		{"after a skipped split", []time.Duration{8 * time.Second, 0, 59 * time.Second}, ComparisonPersonalBest, time.Second, true},
+		{"skipped split", []time.Duration{8 * time.Second, 0}, ComparisonPersonalBest, 0, false},
+		{"best segments", []time.Duration{8 * time.Second, 35 * time.Second}, ComparisonBestSegments, 9 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTimedRun(
+				[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+				[]time.Duration{9 * time.Second, 18 * time.Second, 25 * time.Second},
+			)
+			splits := make([]time.Duration, 3)
+			copy(splits, tt.splits)
+			snapshot := RunSnapshot{
+				State:          state,
+				CurrentSplit:   len(tt.splits),
+				Splits:         splits,
+				Started:        true,
+				Completed:      len(tt.splits) == 3,
+				ComparisonName: tt.comparison,
+			}
+
+			got, ok := snapshot.PreviousSegmentDelta()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("PreviousSegmentDelta = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package sugarSplitCore
+
+// maxEditHistory is how many edits can be undone
+const maxEditHistory = 100
+
+// EditHistory keeps copies of the splits from before each edit, so edits can
+// be undone and redone. The copies are complete, an undone delete brings
+// back the segment's history and gold.
+type EditHistory struct {
+	undo []*LiveSplitState
+	redo []*LiveSplitState
+}
+
+// Record saves the splits from before an edit. It must be a copy that the
+// edit doesn't change, see Clone. Anything undone can't be redone anymore.
+func (h *EditHistory) Record(before *LiveSplitState) {
+	h.undo = append(h.undo, before)
+	if len(h.undo) > maxEditHistory {
+		h.undo = h.undo[1:]
+	}
+	h.redo = nil
+}
+
+// Undo returns the splits from before the last edit, keeping current to redo
+func (h *EditHistory) Undo(current *LiveSplitState) (*LiveSplitState, bool) {
+	if len(h.undo) == 0 {
+		return current, false
+	}
+	previous := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, current)
+	return previous, true
+}
+
+// Redo returns the splits from after the last undone edit
+func (h *EditHistory) Redo(current *LiveSplitState) (*LiveSplitState, bool) {
+	if len(h.redo) == 0 {
+		return current, false
+	}
+	next := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, current)
+	return next, true
+}
+
+// CanUndo reports whether there is an edit to undo
+func (h *EditHistory) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo reports whether there is an undone edit to redo
+func (h *EditHistory) CanRedo() bool {
+	return len(h.redo) > 0
+}
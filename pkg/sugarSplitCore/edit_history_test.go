@@ -0,0 +1,62 @@
+package sugarSplitCore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEditHistoryRestoresDeletedSegment(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second},
+		[]time.Duration{9 * time.Second, 15 * time.Second},
+	)
+	state.Segments.Segments[1].SegmentHistory.Time = []Time{{ID: "1", RealTime: formatDurationLSS(20 * time.Second)}}
+
+	var history EditHistory
+	history.Record(state.Clone())
+	state.RemoveSegment(1)
+
+	restored, ok := history.Undo(state)
+	if !ok {
+		t.Fatalf("nothing to undo")
+	}
+	if n := len(restored.Segments.Segments); n != 2 {
+		t.Fatalf("%d segments after undo, want 2", n)
+	}
+	if got := restored.GoldTime(1); got != 15*time.Second {
+		t.Errorf("gold after undo = %v, want 15s", got)
+	}
+	if n := len(restored.Segments.Segments[1].SegmentHistory.Time); n != 1 {
+		t.Errorf("%d history times after undo, want 1", n)
+	}
+
+	redone, ok := history.Redo(restored)
+	if !ok || len(redone.Segments.Segments) != 1 {
+		t.Errorf("redo didn't delete the segment again")
+	}
+	if history.CanRedo() || !history.CanUndo() {
+		t.Errorf("CanUndo/CanRedo = %v/%v after redo", history.CanUndo(), history.CanRedo())
+	}
+}
+
+func TestEditHistoryRecordClearsRedo(t *testing.T) {
+	state := CreateBlankRun("Game", "Any%")
+
+	var history EditHistory
+	history.Record(state.Clone())
+	state.RenameSegment(0, "First")
+	state, _ = history.Undo(state)
+
+	history.Record(state.Clone())
+	state.RenameSegment(0, "Other")
+	if history.CanRedo() {
+		t.Errorf("redo kept after a new edit")
+	}
+
+	for i := 0; i < maxEditHistory+10; i++ {
+		history.Record(state.Clone())
+	}
+	if len(history.undo) != maxEditHistory {
+		t.Errorf("undo history has %d edits, want %d", len(history.undo), maxEditHistory)
+	}
+}
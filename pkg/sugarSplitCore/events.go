@@ -0,0 +1,114 @@
+package sugarSplitCore
+
+import "time"
+
+// Event is implemented by every event a Run emits to its subscribers.
+// Subscribers should use a type switch to pick the events they care about.
+type Event interface {
+	isEvent()
+}
+
+// StartedEvent is emitted when the timer starts
+type StartedEvent struct {
+	StartTime time.Time
+}
+
+// SplitEvent is emitted after a split has been recorded
+type SplitEvent struct {
+	Index int
+	Time  time.Duration
+	Delta time.Duration
+	Gold  bool
+}
+
+// UndoEvent is emitted when the split at Index has been undone
+type UndoEvent struct {
+	Index int
+}
+
+// SkipEvent is emitted when the split at Index has been skipped
+type SkipEvent struct {
+	Index int
+}
+
+// PausedEvent is emitted when the timer is paused at Time
+type PausedEvent struct {
+	Time time.Duration
+}
+
+// ResumedEvent is emitted when a paused timer resumes from Time
+type ResumedEvent struct {
+	Time time.Duration
+}
+
+// ResetEvent is emitted when the run is reset. Saved reports whether the
+// attempt was written to the splits file before resetting.
+type ResetEvent struct {
+	Saved bool
+}
+
+// PersonalBestEvent is emitted when a finished run is saved as the new PB
+type PersonalBestEvent struct {
+	Time time.Duration
+}
+
+// ComparisonChangedEvent is emitted when the active comparison changes
+type ComparisonChangedEvent struct {
+	Comparison string
+}
+
+func (StartedEvent) isEvent()           {}
+func (SplitEvent) isEvent()             {}
+func (UndoEvent) isEvent()              {}
+func (SkipEvent) isEvent()              {}
+func (PausedEvent) isEvent()            {}
+func (ResumedEvent) isEvent()           {}
+func (ResetEvent) isEvent()             {}
+func (PersonalBestEvent) isEvent()      {}
+func (ComparisonChangedEvent) isEvent() {}
+
+// EventHandler receives events from a Run. Handlers are called synchronously
+// on the goroutine that changed the run, after the change has been applied and
+// without holding the run's lock, so they may read the run through Snapshot.
+// They should return quickly.
+type EventHandler func(Event)
+
+type subscriber struct {
+	id      int
+	handler EventHandler
+}
+
+// Subscribe registers a handler for run events and returns a function that
+// removes it again
+func (r *Run) Subscribe(handler EventHandler) func() {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	r.nextSubscriberID++
+	id := r.nextSubscriberID
+	r.subscribers = append(r.subscribers, subscriber{id: id, handler: handler})
+
+	return func() {
+		r.subMu.Lock()
+		defer r.subMu.Unlock()
+
+		for i, s := range r.subscribers {
+			if s.id == id {
+				r.subscribers = append(r.subscribers[:i], r.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit sends an event to every subscriber in subscription order. It must be
+// called without holding r.mu.
+func (r *Run) emit(e Event) {
+	r.subMu.Lock()
+	subscribers := append([]subscriber(nil), r.subscribers...)
+	r.subMu.Unlock()
+
+	for _, s := range subscribers {
+		s.handler(e)
+	}
+}
@@ -0,0 +1,171 @@
+package sugarSplitCore
+
+import (
+	"strconv"
+	"time"
+)
+
+// AttemptSummary describes one attempt from the attempt history
+type AttemptSummary struct {
+	ID      string
+	Started time.Time
+	// Duration is the final time of a finished attempt, or the time of the
+	// last split reached otherwise
+	Duration time.Duration
+	// Reached is the number of segments the attempt got through
+	Reached  int
+	Finished bool
+	// PB reports whether the attempt was a new personal best when it finished
+	PB bool
+}
+
+// AttemptSplit is one segment of an attempt rebuilt from the segment history
+type AttemptSplit struct {
+	Name        string
+	SegmentTime time.Duration
+	SplitTime   time.Duration
+	Skipped     bool
+}
+
+// segmentHistoryIndex maps every segment's history by attempt ID
+func (state *LiveSplitState) segmentHistoryIndex() []map[string]Time {
+	index := make([]map[string]Time, len(state.Segments.Segments))
+	for i, segment := range state.Segments.Segments {
+		index[i] = make(map[string]Time, len(segment.SegmentHistory.Time))
+		for _, t := range segment.SegmentHistory.Time {
+			index[i][t.ID] = t
+		}
+	}
+	return index
+}
+
+// attemptSplits rebuilds the splits of one attempt, stopping at the first
+// segment the attempt didn't reach
+func (state *LiveSplitState) attemptSplits(history []map[string]Time, id string) []AttemptSplit {
+	var splits []AttemptSplit
+	var total time.Duration
+
+	for i, segment := range state.Segments.Segments {
+		t, ok := history[i][id]
+		if !ok {
+			break
+		}
+
+		split := AttemptSplit{Name: segment.Name, Skipped: t.RealTime == ""}
+		if !split.Skipped {
+			split.SegmentTime = ParseTime(t.RealTime)
+			total += split.SegmentTime
+			split.SplitTime = total
+		}
+		splits = append(splits, split)
+	}
+
+	return splits
+}
+
+// AttemptSplits rebuilds the split-by-split times of an attempt from the segment history
+func (state *LiveSplitState) AttemptSplits(id string) []AttemptSplit {
+	return state.attemptSplits(state.segmentHistoryIndex(), id)
+}
+
+// AttemptSummaries summarizes every attempt in the attempt history, oldest first
+func (state *LiveSplitState) AttemptSummaries() []AttemptSummary {
+	history := state.segmentHistoryIndex()
+	segmentCount := len(state.Segments.Segments)
+	summaries := make([]AttemptSummary, 0, len(state.AttemptHistory.Attempt))
+
+	var best time.Duration
+	for _, attempt := range state.AttemptHistory.Attempt {
+		summary := AttemptSummary{ID: attempt.ID}
+		if started, err := time.Parse(AttemptTimeLayout, attempt.Started); err == nil {
+			summary.Started = started
+		}
+
+		splits := state.attemptSplits(history, attempt.ID)
+		summary.Reached = len(splits)
+		for _, split := range splits {
+			if !split.Skipped {
+				summary.Duration = split.SplitTime
+			}
+		}
+
+		if attempt.RealTime != "" {
+			summary.Duration = ParseTime(attempt.RealTime)
+			summary.Finished = true
+		} else if segmentCount > 0 && summary.Reached == segmentCount && !splits[segmentCount-1].Skipped {
+			summary.Finished = true
+		}
+
+		if summary.Finished && summary.Duration > 0 && (best == 0 || summary.Duration < best) {
+			best = summary.Duration
+			summary.PB = true
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+// nextAttemptID returns the ID to use for a new attempt
+func (state *LiveSplitState) nextAttemptID() int {
+	next := 1
+	for _, attempt := range state.AttemptHistory.Attempt {
+		if id, err := strconv.Atoi(attempt.ID); err == nil && id >= next {
+			next = id + 1
+		}
+	}
+	return next
+}
+
+// migrateSplitHistory converts segment histories saved by sugarSplit builds
+// that stored each attempt's split times rather than segment times, as
+// LiveSplit does. Those files have no version on the Run element and no
+// final times on their attempts, and skipped splits left no entry. An
+// attempt is converted when its times only increase from split to split.
+func (state *LiveSplitState) migrateSplitHistory() {
+	segments := state.Segments.Segments
+	positions := state.historyPositions()
+
+	for _, attempt := range state.AttemptHistory.Attempt {
+		if attempt.RealTime != "" || attempt.GameTime != "" {
+			continue
+		}
+
+		// The segments the attempt has times for, with their split times
+		var reached []int
+		var splits []time.Duration
+		converted := true
+		for i := range segments {
+			k, ok := positions[i][attempt.ID]
+			if !ok {
+				continue
+			}
+			t := ParseTime(segments[i].SegmentHistory.Time[k].RealTime)
+			if t <= 0 || (len(splits) > 0 && t <= splits[len(splits)-1]) {
+				converted = false
+				break
+			}
+			reached = append(reached, i)
+			splits = append(splits, t)
+		}
+		if !converted || len(reached) == 0 {
+			continue
+		}
+
+		// Splits skipped on the way get an empty entry, the segment after
+		// them covers their time
+		var previous time.Duration
+		for n, i := range reached {
+			for j := 0; j < i; j++ {
+				if _, ok := positions[j][attempt.ID]; !ok {
+					segments[j].SegmentHistory.Time = append(segments[j].SegmentHistory.Time, Time{ID: attempt.ID})
+					positions[j][attempt.ID] = len(segments[j].SegmentHistory.Time) - 1
+				}
+			}
+			t := &segments[i].SegmentHistory.Time[positions[i][attempt.ID]]
+			t.RealTime = formatDurationLSS(splits[n] - previous)
+			previous = splits[n]
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package sugarSplitCore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadRunMigratesSplitHistory(t *testing.T) {
+	// Saved by an older sugarSplit: no version, split times in the history
+	// and no entry for the split attempt 2 skipped
+	old := `<Run>
+  <GameName>Game</GameName>
+  <AttemptCount>3</AttemptCount>
+  <AttemptHistory>
+    <Attempt id="1" started="" isStartedSynced="True" ended="" isEndedSynced="True"></Attempt>
+    <Attempt id="2" started="" isStartedSynced="True" ended="" isEndedSynced="True"></Attempt>
+    <Attempt id="3" started="" isStartedSynced="True" ended="" isEndedSynced="True"></Attempt>
+  </AttemptHistory>
+  <Segments>
+    <Segment><Name>A</Name><SegmentHistory>
+      <Time id="1"><RealTime>00:00:10.0000000</RealTime></Time>
+      <Time id="3"><RealTime>00:00:12.0000000</RealTime></Time>
+    </SegmentHistory></Segment>
+    <Segment><Name>B</Name><SegmentHistory>
+      <Time id="1"><RealTime>00:00:25.0000000</RealTime></Time>
+    </SegmentHistory></Segment>
+    <Segment><Name>C</Name><SegmentHistory>
+      <Time id="1"><RealTime>00:00:45.0000000</RealTime></Time>
+      <Time id="2"><RealTime>00:00:50.0000000</RealTime></Time>
+    </SegmentHistory></Segment>
+  </Segments>
+</Run>`
+	path := filepath.Join(t.TempDir(), "old.lss")
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadRun(path)
+	if err != nil {
+		t.Fatalf("LoadRun: %v", err)
+	}
+	if state.Version != LSSVersion {
+		t.Errorf("version = %q, want %q", state.Version, LSSVersion)
+	}
+
+	want := map[string][]time.Duration{
+		"1": {10 * time.Second, 15 * time.Second, 20 * time.Second},
+		"2": {0, 0, 50 * time.Second},
+		"3": {12 * time.Second},
+	}
+	for id, segments := range want {
+		splits := state.AttemptSplits(id)
+		if len(splits) != len(segments) {
+			t.Errorf("attempt %s reached %d splits, want %d", id, len(splits), len(segments))
+			continue
+		}
+		for i, d := range segments {
+			if splits[i].SegmentTime != d || splits[i].Skipped != (d == 0) {
+				t.Errorf("attempt %s split %d = %+v, want segment time %v", id, i, splits[i], d)
+			}
+		}
+	}
+
+	// Once saved with a version the history is left alone
+	if err := SaveRun(state, path); err != nil {
+		t.Fatalf("SaveRun: %v", err)
+	}
+	again, err := LoadRun(path)
+	if err != nil {
+		t.Fatalf("LoadRun: %v", err)
+	}
+	if got := again.AttemptSplits("1")[1].SegmentTime; got != 15*time.Second {
+		t.Errorf("history converted twice, segment time = %v", got)
+	}
+}
+
+func TestLoadRunKeepsLiveSplitHistory(t *testing.T) {
+	// LiveSplit files have a version and segment times already
+	data := `<Run version="1.7.0"><AttemptHistory><Attempt id="1"></Attempt></AttemptHistory><Segments>
+    <Segment><Name>A</Name><SegmentHistory><Time id="1"><RealTime>00:00:10</RealTime></Time></SegmentHistory></Segment>
+    <Segment><Name>B</Name><SegmentHistory><Time id="1"><RealTime>00:00:20</RealTime></Time></SegmentHistory></Segment>
+  </Segments></Run>`
+	path := filepath.Join(t.TempDir(), "livesplit.lss")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadRun(path)
+	if err != nil {
+		t.Fatalf("LoadRun: %v", err)
+	}
+	if got := state.AttemptSplits("1")[1].SegmentTime; got != 20*time.Second {
+		t.Errorf("segment time = %v, want 20s", got)
+	}
+}
@@ -12,15 +12,19 @@ import (
 type Action string
 
 const (
-	ActionSplit     Action = "split"
-	ActionReset     Action = "reset"
-	ActionUndo      Action = "undo"
-	ActionQuit      Action = "quit"
-	ActionConfirm   Action = "confirm"
-	ActionSaveReset Action = "save_reset"
-	ActionCancel    Action = "cancel"
-	ActionSkip      Action = "skip"
-	ActionEdit      Action = "edit"
+	ActionSplit      Action = "split"
+	ActionReset      Action = "reset"
+	ActionUndo       Action = "undo"
+	ActionQuit       Action = "quit"
+	ActionConfirm    Action = "confirm"
+	ActionSaveReset  Action = "save_reset"
+	ActionCancel     Action = "cancel"
+	ActionSkip       Action = "skip"
+	ActionEdit       Action = "edit"
+	ActionPause      Action = "pause"
+	ActionComparison Action = "comparison"
+	ActionHistory    Action = "history"
+	ActionStats      Action = "stats"
 )
 
 type Hotkey struct {
@@ -42,6 +46,10 @@ var defaultHotkeys = []Hotkey{
 	{Key: "esc", Action: ActionCancel, Description: "Cancel"},
 	{Key: "k", Action: ActionSkip, Description: "Skip Split"},
 	{Key: "e", Action: ActionEdit, Description: "Edit Splits"},
+	{Key: "p", Action: ActionPause, Description: "Pause/Resume"},
+	{Key: "c", Action: ActionComparison, Description: "Next Comparison"},
+	{Key: "h", Action: ActionHistory, Description: "History"},
+	{Key: "t", Action: ActionStats, Description: "Statistics"},
 }
 
 // LoadHotkeys loads hotkeys from a TOML file
@@ -67,50 +75,55 @@ func LoadHotkeys(configPath string) ([]Hotkey, error) {
 	return config.Hotkey, nil
 }
 
-// UpdateHotkeyAvailability updates which hotkeys are currently available based on run state
-func (r *Run) UpdateHotkeyAvailability() {
-	for i := range r.Hotkeys {
-		if r.ResettingState {
-			switch r.Hotkeys[i].Action {
+// updateHotkeyAvailability updates which hotkeys are currently available based on
+// run state. It must be called with r.mu held for writing.
+func (r *Run) updateHotkeyAvailability() {
+	for i := range r.hotkeys {
+		if r.resetting {
+			switch r.hotkeys[i].Action {
 			case ActionConfirm, ActionSaveReset, ActionCancel:
-				r.Hotkeys[i].Available = true
+				r.hotkeys[i].Available = true
 			default:
-				r.Hotkeys[i].Available = false
+				r.hotkeys[i].Available = false
 			}
 			continue
 		}
 
-		switch r.Hotkeys[i].Action {
+		switch r.hotkeys[i].Action {
 		case ActionSplit:
-			if !r.Started {
-				r.Hotkeys[i].Available = true
+			if !r.started {
+				r.hotkeys[i].Available = true
 			} else {
-				r.Hotkeys[i].Available = !r.Completed
+				r.hotkeys[i].Available = !r.completed && !r.paused
 			}
 		case ActionReset:
-			r.Hotkeys[i].Available = r.Started || r.Completed
+			r.hotkeys[i].Available = r.started || r.completed
 		case ActionUndo:
-			r.Hotkeys[i].Available = r.Started && r.CurrentSplit > 0 && !r.Completed
+			r.hotkeys[i].Available = r.started && r.currentSplit > 0 && !r.completed
 		case ActionSkip:
-			r.Hotkeys[i].Available = r.Started && !r.Completed && r.CurrentSplit < len(r.State.Segments.Segments)
+			r.hotkeys[i].Available = r.started && !r.completed && r.currentSplit < len(r.state.Segments.Segments)
 		case ActionQuit:
-			r.Hotkeys[i].Available = true
-		case ActionEdit:
-			r.Hotkeys[i].Available = !r.Started && !r.Completed
+			r.hotkeys[i].Available = true
+		case ActionEdit, ActionHistory, ActionStats:
+			r.hotkeys[i].Available = !r.started && !r.completed
+		case ActionPause:
+			r.hotkeys[i].Available = r.started && !r.completed
+		case ActionComparison:
+			r.hotkeys[i].Available = true
 		case ActionConfirm, ActionSaveReset, ActionCancel:
-			r.Hotkeys[i].Available = false
+			r.hotkeys[i].Available = false
 		}
 	}
 }
 
 // GetAvailableHotkeys returns a formatted string of currently available hotkeys
-func (r *Run) GetAvailableHotkeys() string {
+func (s RunSnapshot) GetAvailableHotkeys() string {
 	actionMap := make(map[Action][]string)
 
 	var actions []Action
 	seenActions := make(map[Action]bool)
 
-	for _, hk := range r.Hotkeys {
+	for _, hk := range s.Hotkeys {
 		if hk.Available {
 			actionMap[hk.Action] = append(actionMap[hk.Action], hk.Key)
 			if !seenActions[hk.Action] {
@@ -121,7 +134,7 @@ func (r *Run) GetAvailableHotkeys() string {
 	}
 
 	descMap := make(map[Action]string)
-	for _, hk := range r.Hotkeys {
+	for _, hk := range s.Hotkeys {
 		descMap[hk.Action] = hk.Description
 	}
 
@@ -148,7 +161,11 @@ func (r *Run) GetAction(key string) (Action, bool) {
 	if key == " " {
 		key = "space"
 	}
-	for _, hk := range r.Hotkeys {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, hk := range r.hotkeys {
 		if hk.Key == key && hk.Available {
 			return hk.Action, true
 		}
@@ -0,0 +1,44 @@
+package sugarSplitCore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"strings"
+
+	// Icons are usually PNGs, but LiveSplit accepts any image for them
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ErrNoIcon is returned by DecodeIcon for an empty icon
+var ErrNoIcon = errors.New("no icon")
+
+// DecodeIcon decodes an icon as LiveSplit stores it in a splits file: base64
+// of a .NET BinaryFormatter serialized Bitmap, which wraps the PNG file, or
+// base64 of the image file itself.
+func DecodeIcon(icon string) (image.Image, error) {
+	icon = strings.TrimSpace(icon)
+	if icon == "" {
+		return nil, ErrNoIcon
+	}
+
+	data, err := base64.StdEncoding.DecodeString(icon)
+	if err != nil {
+		return nil, err
+	}
+
+	// The serialized Bitmap has a header before the PNG data, the PNG
+	// decoder stops at the end of the image so trailing bytes don't matter
+	if start := bytes.Index(data, pngSignature); start > 0 {
+		data = data[start:]
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
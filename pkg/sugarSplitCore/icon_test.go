@@ -0,0 +1,64 @@
+package sugarSplitCore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// testPNG encodes a small image with a red pixel in the top left corner
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encoding PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeIcon(t *testing.T) {
+	data := testPNG(t)
+	// LiveSplit's serialized Bitmaps start with a .NET header
+	header := []byte("\x00\x01\x00\x00\x00\xff\xff\xff\xff\x01\x00\x00\x00System.Drawing.Bitmap\x01\x00\x00\x00\x04Data")
+
+	tests := []struct {
+		name string
+		icon string
+	}{
+		{"png", base64.StdEncoding.EncodeToString(data)},
+		{"serialized bitmap", base64.StdEncoding.EncodeToString(append(header, data...))},
+		{"surrounding whitespace", "\n  " + base64.StdEncoding.EncodeToString(data) + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := DecodeIcon(tt.icon)
+			if err != nil {
+				t.Fatalf("DecodeIcon: %v", err)
+			}
+			if got := img.Bounds(); got != image.Rect(0, 0, 3, 2) {
+				t.Errorf("bounds = %v, want 3x2", got)
+			}
+			if r, g, b, a := img.At(0, 0).RGBA(); r != 0xffff || g != 0 || b != 0 || a != 0xffff {
+				t.Errorf("top left pixel = %v %v %v %v, want opaque red", r, g, b, a)
+			}
+		})
+	}
+}
+
+func TestDecodeIconErrors(t *testing.T) {
+	if _, err := DecodeIcon("  "); !errors.Is(err, ErrNoIcon) {
+		t.Errorf("empty icon: err = %v, want ErrNoIcon", err)
+	}
+	if _, err := DecodeIcon("not base64!"); err == nil {
+		t.Error("expected an error for invalid base64")
+	}
+	if _, err := DecodeIcon(base64.StdEncoding.EncodeToString([]byte("no image here"))); err == nil {
+		t.Error("expected an error for data that isn't an image")
+	}
+}
@@ -0,0 +1,298 @@
+package sugarSplitCore
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of other timers ImportRun reads
+const (
+	ImportFormatUrn              = "urn"
+	ImportFormatLlanfair         = "llanfair"
+	ImportFormatWSplit           = "wsplit"
+	ImportFormatTimeSplitTracker = "time_split_tracker"
+	ImportFormatSplitsIO         = "splits.io"
+)
+
+// ImportRun reads splits saved by another timer, recognizing the format from
+// the content. It returns the splits and the name of the format.
+func ImportRun(data []byte) (*LiveSplitState, string, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+
+	var format string
+	var importer func([]byte) (*LiveSplitState, error)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		format, importer = ImportFormatUrn, ImportUrn
+		if bytes.Contains(trimmed, []byte(`"_schemaVersion"`)) {
+			format, importer = ImportFormatSplitsIO, ImportSplitsIO
+		}
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		if bytes.Contains(trimmed, []byte("<GameName>")) {
+			return nil, "", fmt.Errorf("this is already a LiveSplit splits file")
+		}
+		format, importer = ImportFormatLlanfair, ImportLlanfair
+	case bytes.HasPrefix(trimmed, []byte("Title=")):
+		format, importer = ImportFormatWSplit, ImportWSplit
+	case len(trimmed) > 0 && trimmed[0] >= '0' && trimmed[0] <= '9':
+		// Time Split Tracker files start with the attempt count
+		format, importer = ImportFormatTimeSplitTracker, ImportTimeSplitTracker
+	default:
+		return nil, "", fmt.Errorf("unrecognized splits format (supported: Urn, Llanfair, WSplit, Time Split Tracker, splits.io)")
+	}
+
+	state, err := importer(trimmed)
+	if err != nil {
+		return nil, "", err
+	}
+	return state, format, nil
+}
+
+// newImportedRun returns splits with the named segments and nothing else,
+// the importers fill in what their format has
+func newImportedRun(names []string) *LiveSplitState {
+	state := CreateBlankRun("", "")
+	state.Segments.Segments = nil
+	for _, name := range names {
+		state.Segments.Segments = append(state.Segments.Segments, NewSegment(name))
+	}
+	return state
+}
+
+// setImportedTimes sets the PB split time and gold of a segment, leaving out
+// the ones that are missing
+func (state *LiveSplitState) setImportedTimes(index int, pbSplit, gold time.Duration) {
+	segment := &state.Segments.Segments[index]
+	segment.SplitTimes.SplitTime[0].RealTime = formatOptionalLSS(pbSplit)
+	segment.BestSegmentTime.RealTime = formatOptionalLSS(gold)
+}
+
+// parseSeconds parses a time in seconds with a fraction, as WSplit and Time
+// Split Tracker write them. 0 and anything unreadable are no time.
+func parseSeconds(text string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || seconds <= 0 || math.IsInf(seconds, 0) {
+		return 0
+	}
+	return time.Duration(math.Round(seconds*1000)) * time.Millisecond
+}
+
+// Urn stores its splits as JSON. The title is the category, start_delay
+// the time before the timer reaches 0.
+type urnRun struct {
+	Title        string     `json:"title"`
+	AttemptCount int        `json:"attempt_count"`
+	StartDelay   string     `json:"start_delay"`
+	Splits       []urnSplit `json:"splits"`
+}
+
+type urnSplit struct {
+	Title       string `json:"title"`
+	Time        string `json:"time"`
+	BestSegment string `json:"best_segment"`
+}
+
+// ImportUrn reads an Urn splits file. Urn keeps the PB and golds but no
+// history.
+func ImportUrn(data []byte) (*LiveSplitState, error) {
+	var run urnRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("error parsing Urn splits: %v", err)
+	}
+	if len(run.Splits) == 0 {
+		return nil, fmt.Errorf("Urn splits have no splits")
+	}
+
+	names := make([]string, len(run.Splits))
+	for i, split := range run.Splits {
+		names[i] = split.Title
+	}
+	state := newImportedRun(names)
+	state.CategoryName = run.Title
+	state.AttemptCount = max(run.AttemptCount, 0)
+	if delay, err := ParseDuration(run.StartDelay); err == nil && delay > 0 {
+		state.Offset = formatSignedLSS(-delay)
+	}
+
+	for i, split := range run.Splits {
+		pb, _ := ParseDuration(split.Time)
+		gold, _ := ParseDuration(split.BestSegment)
+		state.setImportedTimes(i, max(pb, 0), max(gold, 0))
+	}
+	return state, nil
+}
+
+// Llanfair (Gered's edition) saves its runs as XML. Times are milliseconds,
+// and the PB is kept as segment times rather than split times.
+type llanfairRun struct {
+	XMLName          xml.Name          `xml:"Run"`
+	Name             string            `xml:"name"`
+	SubTitle         string            `xml:"subTitle"`
+	DelayedStart     int64             `xml:"delayedStart"`
+	NumberOfAttempts int               `xml:"numberOfAttempts"`
+	Segments         []llanfairSegment `xml:"segments>Segment"`
+}
+
+type llanfairSegment struct {
+	Name     string       `xml:"name"`
+	RunTime  llanfairTime `xml:"runTime"`
+	BestTime llanfairTime `xml:"bestTime"`
+}
+
+type llanfairTime struct {
+	Milliseconds int64 `xml:"milliseconds"`
+}
+
+func (t llanfairTime) duration() time.Duration {
+	return time.Duration(max(t.Milliseconds, 0)) * time.Millisecond
+}
+
+// ImportLlanfair reads a Llanfair XML run. The name is the game and the
+// subtitle the category.
+func ImportLlanfair(data []byte) (*LiveSplitState, error) {
+	var run llanfairRun
+	if err := xml.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("error parsing Llanfair run: %v", err)
+	}
+	if len(run.Segments) == 0 {
+		return nil, fmt.Errorf("Llanfair run has no segments")
+	}
+
+	names := make([]string, len(run.Segments))
+	for i, segment := range run.Segments {
+		names[i] = segment.Name
+	}
+	state := newImportedRun(names)
+	state.GameName = run.Name
+	state.CategoryName = run.SubTitle
+	state.AttemptCount = max(run.NumberOfAttempts, 0)
+	if run.DelayedStart > 0 {
+		state.Offset = formatSignedLSS(-time.Duration(run.DelayedStart) * time.Millisecond)
+	}
+
+	// PB split times add up the segments, until one of them is missing
+	var split time.Duration
+	for i, segment := range run.Segments {
+		segmentTime := segment.RunTime.duration()
+		if segmentTime <= 0 || (i > 0 && split <= 0) {
+			split = 0
+		} else {
+			split += segmentTime
+		}
+		state.setImportedTimes(i, split, segment.BestTime.duration())
+	}
+	return state, nil
+}
+
+// ImportWSplit reads a WSplit splits file: Key=value settings and a line per
+// split of name,old time,PB split time,gold with times in seconds. Names
+// can contain commas, the times are taken from the end of the line.
+func ImportWSplit(data []byte) (*LiveSplitState, error) {
+	state := newImportedRun(nil)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		key, value, _ := strings.Cut(line, "=")
+		switch key {
+		case "Title":
+			state.CategoryName = value
+			continue
+		case "Attempts":
+			state.AttemptCount, _ = strconv.Atoi(strings.TrimSpace(value))
+			continue
+		case "Offset":
+			// The offset is a start delay in milliseconds
+			if offset, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil && offset > 0 {
+				state.Offset = formatSignedLSS(-time.Duration(offset) * time.Millisecond)
+			}
+			continue
+		case "Size", "Icons", "":
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("error parsing WSplit splits: unexpected line %q", line)
+		}
+		times := fields[len(fields)-3:]
+		name := strings.Join(fields[:len(fields)-3], ",")
+
+		state.Segments.Segments = append(state.Segments.Segments, NewSegment(name))
+		state.setImportedTimes(len(state.Segments.Segments)-1, parseSeconds(times[1]), parseSeconds(times[2]))
+	}
+
+	if len(state.Segments.Segments) == 0 {
+		return nil, fmt.Errorf("WSplit splits have no splits")
+	}
+	state.AttemptCount = max(state.AttemptCount, 0)
+	return state, nil
+}
+
+// ImportTimeSplitTracker reads a Time Split Tracker file. It's tab separated:
+// the attempt count and start delay, then the category and the names of
+// its comparisons, then two lines per segment, one with the name, gold and
+// a split time for each comparison and one with the path of its icon.
+// Times are in seconds, the first comparison is the PB.
+func ImportTimeSplitTracker(data []byte) (*LiveSplitState, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r", ""), "\n")
+	if len(lines) < 3 {
+		return nil, fmt.Errorf("error parsing Time Split Tracker file: too short")
+	}
+
+	header := strings.Split(lines[0], "\t")
+	attempts, err := strconv.Atoi(strings.TrimSpace(header[0]))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Time Split Tracker file: attempt count %q isn't a number", header[0])
+	}
+
+	titles := strings.Split(lines[1], "\t")
+	comparisons := titles[1:]
+
+	state := newImportedRun(nil)
+	state.CategoryName = titles[0]
+	state.AttemptCount = max(attempts, 0)
+	if len(header) > 1 {
+		if delay := parseSeconds(header[1]); delay > 0 {
+			state.Offset = formatSignedLSS(-delay)
+		}
+	}
+
+	// Segment lines alternate with icon lines
+	for i := 2; i < len(lines); i += 2 {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		fields := strings.Split(lines[i], "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("error parsing Time Split Tracker file: line %d has no times", i+1)
+		}
+
+		state.Segments.Segments = append(state.Segments.Segments, NewSegment(fields[0]))
+		index := len(state.Segments.Segments) - 1
+
+		var pb time.Duration
+		if len(fields) > 2 {
+			pb = parseSeconds(fields[2])
+		}
+		state.setImportedTimes(index, pb, parseSeconds(fields[1]))
+
+		// Further comparisons keep their names
+		segment := &state.Segments.Segments[index]
+		for c := 1; c < len(comparisons) && c+2 < len(fields); c++ {
+			if t := parseSeconds(fields[c+2]); t > 0 && comparisons[c] != "" {
+				segment.SplitTimes.SplitTime = append(segment.SplitTimes.SplitTime, SplitTime{Name: comparisons[c], RealTime: formatDurationLSS(t)})
+			}
+		}
+	}
+
+	if len(state.Segments.Segments) == 0 {
+		return nil, fmt.Errorf("Time Split Tracker file has no segments")
+	}
+	return state, nil
+}
@@ -0,0 +1,186 @@
+package sugarSplitCore
+
+import (
+	"testing"
+	"time"
+)
+
+// checkImported compares the names, PB split times and golds of imported
+// splits, 0 meaning no time
+func checkImported(t *testing.T, state *LiveSplitState, names []string, pbs, golds []time.Duration) {
+	t.Helper()
+	if len(state.Segments.Segments) != len(names) {
+		t.Fatalf("got %d segments, want %d", len(state.Segments.Segments), len(names))
+	}
+	for i, segment := range state.Segments.Segments {
+		if segment.Name != names[i] {
+			t.Errorf("segment %d name = %q, want %q", i, segment.Name, names[i])
+		}
+		if got := state.PBSplitTime(i); got != pbs[i] {
+			t.Errorf("segment %d PB split = %v, want %v", i, got, pbs[i])
+		}
+		if got := segment.BestSegmentTime.Time(TimingRealTime); got != golds[i] {
+			t.Errorf("segment %d gold = %v, want %v", i, got, golds[i])
+		}
+	}
+}
+
+func TestImportUrn(t *testing.T) {
+	data := []byte(`{
+		"title": "Any%",
+		"attempt_count": 42,
+		"start_delay": "1.500000",
+		"splits": [
+			{"title": "Forest", "time": "1:05.250000", "best_time": "1:04.000000", "best_segment": "1:03.000000"},
+			{"title": "Castle", "time": "", "best_segment": ""},
+			{"title": "Boss", "time": "1:02:03.000000", "best_segment": "58:00.000000"}
+		]
+	}`)
+
+	state, format, err := ImportRun(data)
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	if format != ImportFormatUrn {
+		t.Errorf("format = %q, want %q", format, ImportFormatUrn)
+	}
+	if state.CategoryName != "Any%" || state.AttemptCount != 42 {
+		t.Errorf("run info = %q, %d attempts", state.CategoryName, state.AttemptCount)
+	}
+	if got := ParseTime(state.Offset); got != -1500*time.Millisecond {
+		t.Errorf("offset = %q, want -1.5s", state.Offset)
+	}
+	checkImported(t, state,
+		[]string{"Forest", "Castle", "Boss"},
+		[]time.Duration{65250 * time.Millisecond, 0, time.Hour + 2*time.Minute + 3*time.Second},
+		[]time.Duration{63 * time.Second, 0, 58 * time.Minute},
+	)
+}
+
+func TestImportLlanfair(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Run>
+  <name>Super Game</name>
+  <subTitle>100%</subTitle>
+  <delayedStart>2000</delayedStart>
+  <numberOfAttempts>7</numberOfAttempts>
+  <segments>
+    <Segment>
+      <name>One</name>
+      <runTime><milliseconds>10000</milliseconds></runTime>
+      <bestTime><milliseconds>9000</milliseconds></bestTime>
+    </Segment>
+    <Segment>
+      <name>Two</name>
+      <runTime><milliseconds>20500</milliseconds></runTime>
+      <bestTime><milliseconds>19000</milliseconds></bestTime>
+    </Segment>
+    <Segment>
+      <name>Three</name>
+      <bestTime><milliseconds>30000</milliseconds></bestTime>
+    </Segment>
+    <Segment>
+      <name>Four</name>
+      <runTime><milliseconds>5000</milliseconds></runTime>
+    </Segment>
+  </segments>
+</Run>`)
+
+	state, format, err := ImportRun(data)
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	if format != ImportFormatLlanfair {
+		t.Errorf("format = %q, want %q", format, ImportFormatLlanfair)
+	}
+	if state.GameName != "Super Game" || state.CategoryName != "100%" || state.AttemptCount != 7 {
+		t.Errorf("run info = %q %q, %d attempts", state.GameName, state.CategoryName, state.AttemptCount)
+	}
+	if got := ParseTime(state.Offset); got != -2*time.Second {
+		t.Errorf("offset = %q, want -2s", state.Offset)
+	}
+	// The PB splits stop adding up at the first missing segment
+	checkImported(t, state,
+		[]string{"One", "Two", "Three", "Four"},
+		[]time.Duration{10 * time.Second, 30500 * time.Millisecond, 0, 0},
+		[]time.Duration{9 * time.Second, 19 * time.Second, 30 * time.Second, 0},
+	)
+}
+
+func TestImportWSplit(t *testing.T) {
+	data := []byte("Title=Game - Low%\r\nAttempts=12\r\nOffset=500\r\nSize=250,400\r\n" +
+		"Start, then run,0,15.5,14.25\r\n" +
+		"Skipped,0,0,10\r\n" +
+		"End,0,60.123,20\r\n" +
+		"Icons=\"\",\"\",\"\"\r\n")
+
+	state, format, err := ImportRun(data)
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	if format != ImportFormatWSplit {
+		t.Errorf("format = %q, want %q", format, ImportFormatWSplit)
+	}
+	if state.CategoryName != "Game - Low%" || state.AttemptCount != 12 {
+		t.Errorf("run info = %q, %d attempts", state.CategoryName, state.AttemptCount)
+	}
+	if got := ParseTime(state.Offset); got != -500*time.Millisecond {
+		t.Errorf("offset = %q, want -0.5s", state.Offset)
+	}
+	checkImported(t, state,
+		[]string{"Start, then run", "Skipped", "End"},
+		[]time.Duration{15500 * time.Millisecond, 0, 60123 * time.Millisecond},
+		[]time.Duration{14250 * time.Millisecond, 10 * time.Second, 20 * time.Second},
+	)
+
+	if _, err := ImportWSplit([]byte("Title=x\nnot a split\n")); err == nil {
+		t.Error("expected an error for a line without times")
+	}
+}
+
+func TestImportTimeSplitTracker(t *testing.T) {
+	data := []byte("3\t1.25\n" +
+		"Glitchless\tPB\tSob\n" +
+		"Level 1\t20\t21.5\t20\n" +
+		"C:\\icons\\1.png\n" +
+		"Level 2\t30\t55\t0\n" +
+		"\n")
+
+	state, format, err := ImportRun(data)
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	if format != ImportFormatTimeSplitTracker {
+		t.Errorf("format = %q, want %q", format, ImportFormatTimeSplitTracker)
+	}
+	if state.CategoryName != "Glitchless" || state.AttemptCount != 3 {
+		t.Errorf("run info = %q, %d attempts", state.CategoryName, state.AttemptCount)
+	}
+	if got := ParseTime(state.Offset); got != -1250*time.Millisecond {
+		t.Errorf("offset = %q, want -1.25s", state.Offset)
+	}
+	checkImported(t, state,
+		[]string{"Level 1", "Level 2"},
+		[]time.Duration{21500 * time.Millisecond, 55 * time.Second},
+		[]time.Duration{20 * time.Second, 30 * time.Second},
+	)
+
+	// The other comparison is kept where it has a time
+	if got := ComparisonSplitTimeFor(state.Segments.Segments, 0, "Sob", TimingRealTime); got != 20*time.Second {
+		t.Errorf("Sob comparison = %v, want 20s", got)
+	}
+	if n := len(state.Segments.Segments[1].SplitTimes.SplitTime); n != 1 {
+		t.Errorf("segment 2 has %d split times, want 1", n)
+	}
+
+	if _, err := ImportTimeSplitTracker([]byte("many\tx\ntitle\nsplit\t1\t2\n")); err == nil {
+		t.Error("expected an error for an attempt count that isn't a number")
+	}
+}
+
+func TestImportRunRejectsLiveSplit(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?><Run version="1.7.0"><GameName>x</GameName></Run>`)
+	if _, _, err := ImportRun(data); err == nil {
+		t.Error("expected an error importing a LiveSplit file")
+	}
+}
@@ -0,0 +1,312 @@
+package sugarSplitCore
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LiveSplit layout (.lsl) structures. Only the settings sugarSplit has an
+// equivalent for are read.
+type lslLayout struct {
+	XMLName    xml.Name       `xml:"Layout"`
+	Mode       string         `xml:"Mode"`
+	Components []lslComponent `xml:"Components>Component"`
+}
+
+type lslComponent struct {
+	Path     string      `xml:"Path"`
+	Settings lslSettings `xml:"Settings"`
+}
+
+// lslSettings keeps the raw settings, every component has its own
+type lslSettings struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+func (s lslSettings) decode(v any) error {
+	return xml.Unmarshal([]byte("<Settings>"+string(s.Inner)+"</Settings>"), v)
+}
+
+type lslSplitsSettings struct {
+	VisualSplitCount    int               `xml:"VisualSplitCount"`
+	SplitPreviewCount   int               `xml:"SplitPreviewCount"`
+	AlwaysShowLastSplit string            `xml:"AlwaysShowLastSplit"`
+	Columns             []lslColumnConfig `xml:"Columns>Settings"`
+}
+
+type lslColumnConfig struct {
+	Name         string `xml:"Name"`
+	Type         string `xml:"Type"`
+	Comparison   string `xml:"Comparison"`
+	TimingMethod string `xml:"TimingMethod"`
+}
+
+type lslTimerSettings struct {
+	Accuracy string `xml:"Accuracy"`
+}
+
+type lslDetailedTimerSettings struct {
+	TimerAccuracy  string `xml:"TimerAccuracy"`
+	HideComparison string `xml:"HideComparison"`
+}
+
+type lslPreviousSegmentSettings struct {
+	ShowPossibleTimeSave string `xml:"ShowPossibleTimeSave"`
+}
+
+type lslGraphSettings struct {
+	Height int `xml:"Height"`
+}
+
+type lslBlankSpaceSettings struct {
+	SpaceHeight int `xml:"SpaceHeight"`
+}
+
+type lslTextSettings struct {
+	Text1 string `xml:"Text1"`
+	Text2 string `xml:"Text2"`
+}
+
+// lslLineHeight is roughly how many pixels of a LiveSplit layout make up a
+// terminal line
+const lslLineHeight = 24
+
+// lslColumnTypes maps LiveSplit's split column types to sugarSplit's. The
+// "or" types show a delta when there is one and fall back to a time, which
+// the delta column doesn't, so they get the closest match.
+var lslColumnTypes = map[string]string{
+	"Delta":                     ColumnDelta,
+	"SplitTime":                 ColumnSplitTime,
+	"DeltaorSplitTime":          ColumnDelta,
+	"SegmentDelta":              ColumnSegmentDelta,
+	"SegmentTime":               ColumnSegmentTime,
+	"SegmentDeltaorSegmentTime": ColumnSegmentDelta,
+}
+
+// lslAccuracy maps LiveSplit's timer accuracy to a number of decimals
+var lslAccuracy = map[string]int{
+	"Seconds":      0,
+	"Tenths":       1,
+	"Hundredths":   2,
+	"Milliseconds": 3,
+}
+
+// LoadLayout reads a LiveSplit .lsl layout and applies its components and
+// their settings on top of base, which is left unchanged. Components that
+// sugarSplit has no equivalent for are skipped and described in the
+// returned warnings.
+func LoadLayout(path string, base *UIConfig) (*UIConfig, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading layout: %v", err)
+	}
+
+	var layout lslLayout
+	if err := xml.Unmarshal(data, &layout); err != nil {
+		return nil, nil, fmt.Errorf("error parsing layout: %v", err)
+	}
+
+	config := *base
+	config.Layout = nil
+	config.Sections = nil
+	config.Splits.Columns = append([]SplitColumnConfig(nil), base.Splits.Columns...)
+
+	var warnings []string
+	if layout.Mode == "Horizontal" {
+		warnings = append(warnings, "horizontal layouts are shown vertically")
+	}
+
+	// Everything above the split list goes on top and everything below it
+	// at the bottom, like the layout's vertical order
+	section := SectionTop
+	for _, component := range layout.Components {
+		name := strings.TrimSuffix(filepath.Base(strings.ReplaceAll(component.Path, `\`, "/")), ".dll")
+		placement, err := config.applyLSLComponent(name, component.Settings, &warnings)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing layout component %s: %v", name, err)
+		}
+		if placement == "" {
+			continue
+		}
+
+		placed := UIComponentConfig{Component: placement, Section: section}
+		switch placement {
+		case UISplits:
+			placed.Section = SectionMiddle
+			section = SectionBottom
+		case UIPreviousSegment:
+			var settings lslPreviousSegmentSettings
+			if err := component.Settings.decode(&settings); err != nil {
+				return nil, nil, err
+			}
+			placed.ShowPossibleTimeSave = lslBool(settings.ShowPossibleTimeSave)
+		case UIBlank:
+			var settings lslBlankSpaceSettings
+			if err := component.Settings.decode(&settings); err != nil {
+				return nil, nil, err
+			}
+			placed.Lines = max(settings.SpaceHeight/lslLineHeight, 1)
+		case UIText:
+			var settings lslTextSettings
+			if err := component.Settings.decode(&settings); err != nil {
+				return nil, nil, err
+			}
+			placed.Text = strings.TrimSpace(settings.Text1 + "  " + settings.Text2)
+			placed.Align = AlignLeft
+			if settings.Text1 == "" || settings.Text2 == "" {
+				placed.Align = AlignCenter
+			}
+		}
+		config.Sections = append(config.Sections, placed)
+	}
+
+	if len(config.Sections) == 0 {
+		return nil, warnings, fmt.Errorf("layout %s has no components sugarSplit can show", path)
+	}
+	return &config, warnings, nil
+}
+
+// applyLSLComponent applies the settings of a layout component that are
+// shared by every instance of it and returns the sugarSplit component it
+// becomes, or "" if there is none
+func (c *UIConfig) applyLSLComponent(name string, settings lslSettings, warnings *[]string) (UIComponent, error) {
+	switch name {
+	case "LiveSplit.Title":
+		return UITitle, nil
+
+	case "LiveSplit.Splits", "LiveSplit.Subsplits":
+		var s lslSplitsSettings
+		if err := settings.decode(&s); err != nil {
+			return "", err
+		}
+		c.Splits.Visible = max(s.VisualSplitCount, 0)
+		c.Splits.Upcoming = max(s.SplitPreviewCount, 0)
+		c.Splits.PinLast = lslBool(s.AlwaysShowLastSplit)
+
+		// Layouts from before columns were configurable keep the defaults
+		if len(s.Columns) > 0 {
+			c.Splits.Columns = nil
+		}
+		for _, column := range s.Columns {
+			columnType, ok := lslColumnTypes[column.Type]
+			if !ok {
+				*warnings = append(*warnings, fmt.Sprintf("split column %q has unsupported type %s, skipped", column.Name, column.Type))
+				continue
+			}
+			c.Splits.Columns = append(c.Splits.Columns, SplitColumnConfig{
+				Type:         columnType,
+				Comparison:   lslComparison(column.Comparison),
+				TimingMethod: lslTimingMethod(column.TimingMethod),
+			})
+		}
+		if name == "LiveSplit.Subsplits" {
+			*warnings = append(*warnings, "subsplits are shown as a flat split list")
+		}
+		return UISplits, nil
+
+	case "LiveSplit.Timer":
+		var s lslTimerSettings
+		if err := settings.decode(&s); err != nil {
+			return "", err
+		}
+		if accuracy, ok := lslAccuracy[s.Accuracy]; ok {
+			c.Timer.Accuracy = accuracy
+		}
+		return UITimer, nil
+
+	case "LiveSplit.DetailedTimer":
+		var s lslDetailedTimerSettings
+		if err := settings.decode(&s); err != nil {
+			return "", err
+		}
+		if accuracy, ok := lslAccuracy[s.TimerAccuracy]; ok {
+			c.Timer.Accuracy = accuracy
+		}
+		c.DetailedTimer.ShowComparison = !lslBool(s.HideComparison)
+		return UIDetailedTimer, nil
+
+	case "LiveSplit.PreviousSegment":
+		return UIPreviousSegment, nil
+
+	case "LiveSplit.SumOfBest":
+		return UISumOfBest, nil
+
+	case "LiveSplit.PossibleTimeSave":
+		return UIPossibleTimeSave, nil
+
+	case "LiveSplit.Graph":
+		var s lslGraphSettings
+		if err := settings.decode(&s); err != nil {
+			return "", err
+		}
+		if s.Height > 0 {
+			c.Graph.Height = max(s.Height/lslLineHeight, 2)
+		}
+		return UIGraph, nil
+
+	case "LiveSplit.Text":
+		return UIText, nil
+
+	case "LiveSplit.Separator":
+		return UISeparator, nil
+
+	case "LiveSplit.BlankSpace":
+		return UIBlank, nil
+
+	case "PBChance", "LiveSplit.PBChance":
+		return UIPBChance, nil
+	}
+
+	*warnings = append(*warnings, fmt.Sprintf("unsupported component %s, skipped", name))
+	return "", nil
+}
+
+// lslBool parses a LiveSplit setting, which writes booleans as True and False
+func lslBool(value string) bool {
+	b, _ := strconv.ParseBool(strings.ToLower(strings.TrimSpace(value)))
+	return b
+}
+
+// lslComparison returns the comparison of a column, "" for the active one
+func lslComparison(comparison string) string {
+	if comparison == "Current Comparison" {
+		return ""
+	}
+	return comparison
+}
+
+func lslTimingMethod(method string) string {
+	switch method {
+	case "Real Time":
+		return TimingRealTime
+	case "Game Time":
+		return TimingGameTime
+	}
+	return ""
+}
+
+// ResolveLayoutPath finds the layout a splits file refers to. LiveSplit
+// stores the path as it was on the machine that saved the splits, often a
+// Windows path, so a layout next to the splits file with the same name is
+// used when the path itself doesn't exist.
+func ResolveLayoutPath(splitsPath, layoutPath string) (string, error) {
+	dir := filepath.Dir(splitsPath)
+	slashed := strings.ReplaceAll(layoutPath, `\`, "/")
+
+	candidates := []string{layoutPath}
+	if !filepath.IsAbs(slashed) && !strings.Contains(slashed, ":") {
+		candidates = append(candidates, filepath.Join(dir, slashed))
+	}
+	candidates = append(candidates, filepath.Join(dir, filepath.Base(slashed)))
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("layout %s not found", layoutPath)
+}
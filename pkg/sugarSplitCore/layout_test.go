@@ -0,0 +1,86 @@
+package sugarSplitCore
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLayout(t *testing.T) {
+	base := DefaultUIConfig()
+	baseColumns := append([]SplitColumnConfig(nil), base.Splits.Columns...)
+
+	config, warnings, err := LoadLayout(filepath.Join("testdata", "team.lsl"), base)
+	if err != nil {
+		t.Fatalf("LoadLayout: %v", err)
+	}
+
+	// Components above the splits go on top and the ones below at the bottom
+	wantSections := []UIComponentConfig{
+		{Component: UITitle, Section: SectionTop},
+		{Component: UIText, Section: SectionTop, Text: "Goal  Sub 1h", Align: AlignLeft},
+		{Component: UISplits, Section: SectionMiddle},
+		{Component: UITimer, Section: SectionBottom},
+		{Component: UIBlank, Section: SectionBottom, Lines: 2},
+		{Component: UIText, Section: SectionBottom, Text: "Thanks for watching", Align: AlignCenter},
+	}
+	if !reflect.DeepEqual(config.Sections, wantSections) {
+		t.Errorf("sections = %+v\nwant %+v", config.Sections, wantSections)
+	}
+
+	wantColumns := []SplitColumnConfig{
+		{Type: ColumnDelta},
+		{Type: ColumnSplitTime, Comparison: ComparisonBestSegments, TimingMethod: TimingGameTime},
+	}
+	if !reflect.DeepEqual(config.Splits.Columns, wantColumns) {
+		t.Errorf("columns = %+v, want %+v", config.Splits.Columns, wantColumns)
+	}
+	if config.Splits.Visible != 8 || config.Splits.Upcoming != 1 || !config.Splits.PinLast {
+		t.Errorf("splits = %d visible, %d upcoming, pin last %v", config.Splits.Visible, config.Splits.Upcoming, config.Splits.PinLast)
+	}
+	if config.Timer.Accuracy != 1 {
+		t.Errorf("timer accuracy = %d, want 1", config.Timer.Accuracy)
+	}
+
+	wantWarnings := []string{
+		`split column "Deaths" has unsupported type CustomVariable, skipped`,
+		"unsupported component LiveSplit.Counter, skipped",
+	}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings = %q, want %q", warnings, wantWarnings)
+	}
+
+	// The base config stays as it was
+	if !reflect.DeepEqual(base.Splits.Columns, baseColumns) || base.Sections != nil {
+		t.Errorf("base config changed: %+v", base)
+	}
+}
+
+func TestResolveLayoutPath(t *testing.T) {
+	dir := t.TempDir()
+	splits := filepath.Join(dir, "any.lss")
+	layout := filepath.Join(dir, "team.lsl")
+	if err := os.WriteFile(layout, []byte("<Layout/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		layoutPath string
+	}{
+		{"existing path", layout},
+		{"relative path", "team.lsl"},
+		{"Windows path from another machine", `C:\Users\runner\LiveSplit\Layouts\team.lsl`},
+	}
+	for _, tt := range tests {
+		got, err := ResolveLayoutPath(splits, tt.layoutPath)
+		if err != nil || got != layout {
+			t.Errorf("%s: ResolveLayoutPath(%q) = %q, %v, want %q", tt.name, tt.layoutPath, got, err, layout)
+		}
+	}
+
+	if _, err := ResolveLayoutPath(splits, `C:\Layouts\missing.lsl`); err == nil {
+		t.Error("expected an error for a layout that doesn't exist")
+	}
+}
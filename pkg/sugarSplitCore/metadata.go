@@ -0,0 +1,151 @@
+package sugarSplitCore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RunField is a piece of run information that is edited as text
+type RunField string
+
+const (
+	FieldGameName     RunField = "Game"
+	FieldCategoryName RunField = "Category"
+	FieldOffset       RunField = "Offset"
+	FieldAttemptCount RunField = "Attempts"
+	FieldPlatform     RunField = "Platform"
+	FieldEmulator     RunField = "Emulator"
+	FieldRegion       RunField = "Region"
+	FieldRunID        RunField = "Run ID"
+)
+
+// RunFields lists the run fields in the order edit mode shows them
+var RunFields = []RunField{
+	FieldGameName,
+	FieldCategoryName,
+	FieldOffset,
+	FieldAttemptCount,
+	FieldPlatform,
+	FieldEmulator,
+	FieldRegion,
+	FieldRunID,
+}
+
+// Field returns the text of a run field
+func (state *LiveSplitState) Field(field RunField) string {
+	switch field {
+	case FieldGameName:
+		return state.GameName
+	case FieldCategoryName:
+		return state.CategoryName
+	case FieldOffset:
+		return formatOffset(ParseTime(state.Offset))
+	case FieldAttemptCount:
+		return strconv.Itoa(state.AttemptCount)
+	case FieldPlatform:
+		return state.Metadata.Platform.Name
+	case FieldEmulator:
+		if state.Metadata.Platform.UsesEmulator {
+			return "yes"
+		}
+		return "no"
+	case FieldRegion:
+		return state.Metadata.Region
+	case FieldRunID:
+		return state.Metadata.Run.ID
+	}
+	return ""
+}
+
+// SetField changes a run field from text, leaving it unchanged when the
+// text isn't valid for it
+func (state *LiveSplitState) SetField(field RunField, value string) error {
+	value = strings.TrimSpace(value)
+
+	switch field {
+	case FieldGameName:
+		state.GameName = value
+	case FieldCategoryName:
+		state.CategoryName = value
+	case FieldOffset:
+		offset, err := ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		state.Offset = formatSignedLSS(offset)
+	case FieldAttemptCount:
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return fmt.Errorf("attempts must be a whole number of at least 0")
+		}
+		state.AttemptCount = count
+	case FieldPlatform:
+		state.Metadata.Platform.Name = value
+	case FieldEmulator:
+		switch strings.ToLower(value) {
+		case "yes", "y", "true":
+			state.Metadata.Platform.UsesEmulator = true
+		case "no", "n", "false":
+			state.Metadata.Platform.UsesEmulator = false
+		default:
+			return fmt.Errorf("emulator must be yes or no")
+		}
+	case FieldRegion:
+		state.Metadata.Region = value
+	case FieldRunID:
+		state.Metadata.Run.ID = value
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// SetVariable sets the speedrun.com variable at index, or adds one when
+// index is past the last variable. Names are unique.
+func (state *LiveSplitState) SetVariable(index int, name, value string) error {
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if name == "" {
+		return fmt.Errorf("variables need a name")
+	}
+
+	variables := state.Metadata.Variables.Variable
+	for i, variable := range variables {
+		if i != index && variable.Name == name {
+			return fmt.Errorf("variable %q already exists", name)
+		}
+	}
+
+	variable := MetadataVariable{Name: name, Value: value}
+	if index >= 0 && index < len(variables) {
+		variables[index] = variable
+	} else {
+		state.Metadata.Variables.Variable = append(variables, variable)
+	}
+	return nil
+}
+
+// RemoveVariable removes the speedrun.com variable at index
+func (state *LiveSplitState) RemoveVariable(index int) {
+	variables := state.Metadata.Variables.Variable
+	if index >= 0 && index < len(variables) {
+		state.Metadata.Variables.Variable = append(variables[:index], variables[index+1:]...)
+	}
+}
+
+// formatOffset formats an offset for editing, with a sign when negative
+func formatOffset(d time.Duration) string {
+	if d < 0 {
+		return "-" + FormatDuration(-d)
+	}
+	return FormatDuration(d)
+}
+
+// formatSignedLSS is formatDurationLSS for times that can be negative
+func formatSignedLSS(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatDurationLSS(-d)
+	}
+	return formatDurationLSS(d)
+}
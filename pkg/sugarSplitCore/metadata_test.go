@@ -0,0 +1,88 @@
+package sugarSplitCore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	valid := map[string]time.Duration{
+		"5":           5 * time.Second,
+		"1.5":         1500 * time.Millisecond,
+		"01:02.030":   time.Minute + 2*time.Second + 30*time.Millisecond,
+		"1:00:00":     time.Hour,
+		"-00:05.250":  -5250 * time.Millisecond,
+		" 12:34 ":     12*time.Minute + 34*time.Second,
+		"0.123456789": 123456789,
+	}
+	for text, want := range valid {
+		got, err := ParseDuration(text)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", text, got, err, want)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "1:60", "1.5:00", "1:2:3:4", "1.", "--1", "1:-2"} {
+		if got, err := ParseDuration(text); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, want an error", text, got)
+		}
+	}
+}
+
+func TestSetField(t *testing.T) {
+	state := CreateBlankRun("Game", "Any%")
+
+	if err := state.SetField(FieldOffset, "-1.5"); err != nil {
+		t.Fatalf("SetField offset: %v", err)
+	}
+	if got := ParseTime(state.Offset); got != -1500*time.Millisecond {
+		t.Errorf("offset = %v, want -1.5s", got)
+	}
+	if got := state.Field(FieldOffset); got != "-00:01.500" {
+		t.Errorf("offset field = %q", got)
+	}
+
+	if err := state.SetField(FieldAttemptCount, "-3"); err == nil {
+		t.Errorf("negative attempt count accepted")
+	}
+	if err := state.SetField(FieldOffset, "soon"); err == nil || state.Field(FieldOffset) != "-00:01.500" {
+		t.Errorf("invalid offset changed the field")
+	}
+
+	if err := state.SetField(FieldEmulator, "yes"); err != nil || !state.Metadata.Platform.UsesEmulator {
+		t.Errorf("emulator not set: %v", err)
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	state := CreateBlankRun("Game", "Any%")
+	state.SetField(FieldPlatform, "GameCube")
+	state.SetField(FieldEmulator, "yes")
+	state.SetField(FieldRegion, "USA / NTSC")
+	state.SetField(FieldRunID, "abc123")
+	if err := state.SetVariable(0, "Difficulty", "Hard"); err != nil {
+		t.Fatalf("SetVariable: %v", err)
+	}
+	if err := state.SetVariable(1, "Difficulty", "Easy"); err == nil {
+		t.Errorf("duplicate variable accepted")
+	}
+
+	path := filepath.Join(t.TempDir(), "run.lss")
+	if err := SaveRun(state, path); err != nil {
+		t.Fatalf("SaveRun: %v", err)
+	}
+	loaded, err := LoadRun(path)
+	if err != nil {
+		t.Fatalf("LoadRun: %v", err)
+	}
+
+	for _, field := range RunFields {
+		if got, want := loaded.Field(field), state.Field(field); got != want {
+			t.Errorf("%s = %q after loading, want %q", field, got, want)
+		}
+	}
+	if variables := loaded.Metadata.Variables.Variable; len(variables) != 1 || variables[0] != (MetadataVariable{Name: "Difficulty", Value: "Hard"}) {
+		t.Errorf("variables = %v after loading", variables)
+	}
+}
@@ -0,0 +1,122 @@
+package sugarSplitCore
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// DefaultPredictionSamples is the number of runs a Predictor simulates
+const DefaultPredictionSamples = 1000
+
+// Predictor estimates how a run ends by simulating the remaining segments
+// with times drawn from their history. Every prediction restarts the random
+// number generator from the same seed, so the same input always gives the
+// same result.
+type Predictor struct {
+	Samples int
+
+	state    *LiveSplitState
+	seed     int64
+	segments [][]time.Duration
+	pb       time.Duration
+}
+
+// Prediction is the outcome of simulating the rest of a run
+type Prediction struct {
+	// PBChance is the share of simulated runs that finished under the PB,
+	// assuming the run isn't reset
+	PBChance float64
+	// ExpectedFinal is the average final time of the simulated runs
+	ExpectedFinal time.Duration
+	// Samples is the number of simulated runs, 0 if there wasn't enough data
+	Samples int
+}
+
+// NewPredictor prepares a predictor for the given splits
+func NewPredictor(state *LiveSplitState, seed int64) *Predictor {
+	samples := state.SegmentSamples()
+	segments := state.Segments.Segments
+
+	for i := range samples {
+		sort.Slice(samples[i], func(a, b int) bool { return samples[i][a] < samples[i][b] })
+
+		// Without history fall back to the gold, then to the PB segment
+		if len(samples[i]) == 0 {
+			if gold := ParseTime(segments[i].BestSegmentTime.RealTime); gold > 0 {
+				samples[i] = []time.Duration{gold}
+			} else if pb := pbSegmentTime(segments, i); pb > 0 {
+				samples[i] = []time.Duration{pb}
+			}
+		}
+	}
+
+	return &Predictor{
+		Samples:  DefaultPredictionSamples,
+		state:    state,
+		seed:     seed,
+		segments: samples,
+		pb:       ComparisonSplitTime(segments, len(segments)-1, ComparisonPersonalBest),
+	}
+}
+
+// State returns the splits the predictor was prepared for
+func (p *Predictor) State() *LiveSplitState {
+	return p.state
+}
+
+// Predict simulates the rest of a run that is in the segment currentSplit,
+// which started at lastSplitTime, with the timer at currentTime. Pass a
+// currentSplit of 0 and zero times to predict a run that hasn't started.
+func (p *Predictor) Predict(currentSplit int, lastSplitTime, currentTime time.Duration) Prediction {
+	if currentSplit < 0 {
+		currentSplit = 0
+	}
+	if currentSplit >= len(p.segments) {
+		return Prediction{}
+	}
+	for _, samples := range p.segments[currentSplit:] {
+		if len(samples) == 0 {
+			return Prediction{}
+		}
+	}
+
+	rng := rand.New(rand.NewSource(p.seed))
+	elapsed := currentTime - lastSplitTime
+
+	// Only times longer than what has already passed are possible for the
+	// current segment
+	current := p.segments[currentSplit]
+	longer := current[sort.Search(len(current), func(i int) bool { return current[i] > elapsed }):]
+
+	underPB := 0
+	var total float64
+	for n := 0; n < p.Samples; n++ {
+		final := lastSplitTime
+		if len(longer) > 0 {
+			final += longer[rng.Intn(len(longer))]
+		} else {
+			final += elapsed
+		}
+
+		for _, samples := range p.segments[currentSplit+1:] {
+			final += samples[rng.Intn(len(samples))]
+		}
+
+		if p.pb == 0 || final < p.pb {
+			underPB++
+		}
+		total += float64(final)
+	}
+
+	return Prediction{
+		PBChance:      float64(underPB) / float64(p.Samples),
+		ExpectedFinal: time.Duration(total / float64(p.Samples)),
+		Samples:       p.Samples,
+	}
+}
+
+// pbSegmentTime returns the PB duration of a segment, or 0 if unknown
+func pbSegmentTime(segments []Segment, index int) time.Duration {
+	return comparisonSegmentTime(segments, index, ComparisonPersonalBest, TimingRealTime)
+}
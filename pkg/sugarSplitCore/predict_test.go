@@ -0,0 +1,72 @@
+package sugarSplitCore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// predictionState builds splits whose segments have the given histories,
+// with a PB of pb
+func predictionState(pb time.Duration, histories ...[]time.Duration) *LiveSplitState {
+	state := CreateBlankRun("Test Game", "Any%")
+	for i := 1; i < len(histories); i++ {
+		state.AddSegment(i-1, fmt.Sprintf("Split %d", i+1))
+	}
+
+	for i, history := range histories {
+		segment := &state.Segments.Segments[i]
+		for id, d := range history {
+			segment.SegmentHistory.Time = append(segment.SegmentHistory.Time,
+				Time{ID: fmt.Sprint(id + 1), RealTime: formatDurationLSS(d)})
+		}
+	}
+	last := len(histories) - 1
+	state.Segments.Segments[last].SplitTimes.SplitTime[0].RealTime = formatDurationLSS(pb)
+	return state
+}
+
+func TestPredictIsDeterministic(t *testing.T) {
+	state := predictionState(50*time.Second,
+		[]time.Duration{10 * time.Second, 12 * time.Second, 15 * time.Second, 20 * time.Second},
+		[]time.Duration{20 * time.Second, 25 * time.Second, 30 * time.Second, 40 * time.Second},
+	)
+
+	first := NewPredictor(state, 42).Predict(0, 0, 0)
+	second := NewPredictor(state, 42).Predict(0, 0, 0)
+	if first != second {
+		t.Errorf("same seed gave %+v and %+v", first, second)
+	}
+	if first.PBChance <= 0 || first.PBChance >= 1 {
+		t.Errorf("PB chance %v should be strictly between 0 and 1", first.PBChance)
+	}
+}
+
+func TestPredictBounds(t *testing.T) {
+	state := predictionState(time.Minute,
+		[]time.Duration{10 * time.Second, 11 * time.Second},
+		[]time.Duration{20 * time.Second, 21 * time.Second},
+	)
+	predictor := NewPredictor(state, 1)
+
+	if got := predictor.Predict(0, 0, 0); got.PBChance != 1 {
+		t.Errorf("every simulated run is under PB, got chance %v", got.PBChance)
+	}
+
+	// Already past the PB in the last segment
+	if got := predictor.Predict(1, 55*time.Second, 70*time.Second); got.PBChance != 0 {
+		t.Errorf("run already behind PB, got chance %v", got.PBChance)
+	}
+
+	got := predictor.Predict(1, 10*time.Second, 10*time.Second)
+	if got.ExpectedFinal < 30*time.Second || got.ExpectedFinal > 31*time.Second {
+		t.Errorf("expected final %v outside the possible 30s-31s", got.ExpectedFinal)
+	}
+}
+
+func TestPredictWithoutData(t *testing.T) {
+	state := CreateBlankRun("Test Game", "Any%")
+	if got := NewPredictor(state, 1).Predict(0, 0, 0); got.Samples != 0 {
+		t.Errorf("prediction without history should be empty, got %+v", got)
+	}
+}
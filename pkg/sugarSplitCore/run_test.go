@@ -0,0 +1,319 @@
+package sugarSplitCore
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestRun(t *testing.T, segments int) *Run {
+	t.Helper()
+
+	state := CreateBlankRun("Test Game", "Any%")
+	for i := 1; i < segments; i++ {
+		state.AddSegment(i-1, "Split")
+	}
+
+	run, err := NewRun(state, filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("NewRun: %v", err)
+	}
+	return run
+}
+
+func checkSnapshot(t *testing.T, s RunSnapshot) {
+	n := len(s.State.Segments.Segments)
+	if len(s.Splits) != n || len(s.IsGold) != n || len(s.Comparison) != n {
+		t.Errorf("snapshot arrays sized %d/%d/%d for %d segments",
+			len(s.Splits), len(s.IsGold), len(s.Comparison), n)
+	}
+	if s.CurrentSplit < -1 || s.CurrentSplit > n {
+		t.Errorf("current split %d out of range for %d segments", s.CurrentSplit, n)
+	}
+	if s.Started && s.Completed {
+		t.Errorf("run is both started and completed")
+	}
+}
+
+func TestRunConcurrentUse(t *testing.T) {
+	run := newTestRun(t, 10)
+	filename := filepath.Join(t.TempDir(), "run.lss")
+
+	unsubscribe := run.Subscribe(func(e Event) {
+		// Handlers run without the lock held, so reading the run must not deadlock
+		checkSnapshot(t, run.Snapshot())
+	})
+	defer unsubscribe()
+
+	var wg sync.WaitGroup
+	worker := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				fn(i)
+			}
+		}()
+	}
+
+	worker(func(int) { run.Start(time.Now()) })
+	worker(func(int) { run.Split(time.Now()) })
+	worker(func(int) { run.Tick(time.Now()) })
+	worker(func(int) { run.UndoSplit(time.Now()) })
+	worker(func(int) { run.SkipSplit() })
+	worker(func(i int) {
+		if i%2 == 0 {
+			run.Pause(time.Now())
+		} else {
+			run.Resume(time.Now())
+		}
+	})
+	worker(func(i int) {
+		switch i % 20 {
+		case 0:
+			run.BeginReset()
+		case 5:
+			run.CancelReset()
+		case 10:
+			run.Reset()
+		case 15:
+			if err := run.SaveAndReset(filename); err != nil {
+				t.Errorf("SaveAndReset: %v", err)
+			}
+		}
+	})
+	worker(func(int) { run.NextComparison() })
+	worker(func(int) {
+		s := run.Snapshot()
+		checkSnapshot(t, s)
+		_ = s.GetAvailableHotkeys()
+		_ = s.IsPB()
+	})
+	worker(func(int) { run.GetAction("space") })
+	worker(func(int) {
+		unsubscribe := run.Subscribe(func(Event) {})
+		unsubscribe()
+	})
+	worker(func(i int) {
+		if i%50 == 0 {
+			state := run.State().Clone()
+			state.AddSegment(0, "Added")
+			run.SetState(state)
+		}
+	})
+
+	wg.Wait()
+	checkSnapshot(t, run.Snapshot())
+}
+
+func TestSnapshotIsIsolated(t *testing.T) {
+	run := newTestRun(t, 3)
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(10 * time.Second))
+
+	snapshot := run.Snapshot()
+	snapshot.Splits[0] = time.Hour
+	snapshot.IsGold[0] = false
+
+	again := run.Snapshot()
+	if again.Splits[0] != 10*time.Second {
+		t.Errorf("run split changed through snapshot: %v", again.Splits[0])
+	}
+	if !again.IsGold[0] {
+		t.Errorf("run gold changed through snapshot")
+	}
+}
+
+func TestSaveRunKeepsSnapshotStateUnchanged(t *testing.T) {
+	run := newTestRun(t, 1)
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(time.Minute))
+
+	before := run.Snapshot()
+	if err := run.SaveRun(filepath.Join(t.TempDir(), "run.lss")); err != nil {
+		t.Fatalf("SaveRun: %v", err)
+	}
+
+	if got := len(before.State.AttemptHistory.Attempt); got != 0 {
+		t.Errorf("snapshot state gained %d attempts after SaveRun", got)
+	}
+	if got := len(run.State().AttemptHistory.Attempt); got != 1 {
+		t.Errorf("run state has %d attempts after SaveRun, want 1", got)
+	}
+}
+
+func TestRunEvents(t *testing.T) {
+	run := newTestRun(t, 2)
+
+	var got []Event
+	unsubscribe := run.Subscribe(func(e Event) { got = append(got, e) })
+
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(5 * time.Second))
+	run.UndoSplit(start.Add(6 * time.Second))
+	run.SkipSplit()
+	run.Reset()
+	unsubscribe()
+	run.Start(start)
+
+	want := []Event{
+		StartedEvent{StartTime: start},
+		SplitEvent{Index: 0, Time: 5 * time.Second, Delta: 5 * time.Second, Gold: true},
+		UndoEvent{Index: 0},
+		SkipEvent{Index: 0},
+		ResetEvent{Saved: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSaveAndResetEvents(t *testing.T) {
+	run := newTestRun(t, 2)
+
+	var got []Event
+	run.Subscribe(func(e Event) {
+		switch e.(type) {
+		case PersonalBestEvent, ResetEvent:
+			got = append(got, e)
+		}
+	})
+
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(20 * time.Second))
+	run.Split(start.Add(45 * time.Second))
+	if err := run.SaveAndReset(filepath.Join(t.TempDir(), "run.lss")); err != nil {
+		t.Fatalf("SaveAndReset: %v", err)
+	}
+
+	want := []Event{
+		PersonalBestEvent{Time: 45 * time.Second},
+		ResetEvent{Saved: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSaveRunFailureKeepsState(t *testing.T) {
+	run := newTestRun(t, 1)
+
+	var got []Event
+	run.Subscribe(func(e Event) {
+		switch e.(type) {
+		case PersonalBestEvent, ResetEvent:
+			got = append(got, e)
+		}
+	})
+
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(time.Minute))
+	before := run.State()
+
+	missing := filepath.Join(t.TempDir(), "missing", "run.lss")
+	if err := run.SaveAndReset(missing); err == nil {
+		t.Fatal("SaveAndReset succeeded writing to a missing directory")
+	}
+
+	if run.State() != before {
+		t.Error("run state replaced after a failed save")
+	}
+	if n := len(run.State().AttemptHistory.Attempt); n != 0 {
+		t.Errorf("run state has %d attempts after a failed save, want 0", n)
+	}
+	if len(got) != 1 || got[0] != (ResetEvent{Saved: false}) {
+		t.Errorf("events = %#v, want only an unsaved reset", got)
+	}
+}
+
+func TestRunPauseEvents(t *testing.T) {
+	run := newTestRun(t, 2)
+
+	var got []Event
+	run.Subscribe(func(e Event) { got = append(got, e) })
+
+	start := time.Now()
+	run.Start(start)
+	run.Pause(start.Add(3 * time.Second))
+	// Splitting and pausing again do nothing while paused
+	run.Split(start.Add(4 * time.Second))
+	run.Pause(start.Add(5 * time.Second))
+	run.Resume(start.Add(10 * time.Second))
+	run.Resume(start.Add(11 * time.Second))
+	// The 7 seconds spent paused don't count
+	run.Split(start.Add(12 * time.Second))
+
+	want := []Event{
+		StartedEvent{StartTime: start},
+		PausedEvent{Time: 3 * time.Second},
+		ResumedEvent{Time: 3 * time.Second},
+		SplitEvent{Index: 0, Time: 5 * time.Second, Delta: 5 * time.Second, Gold: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRunComparisonEvents(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second},
+		[]time.Duration{8 * time.Second, 15 * time.Second},
+	)
+	run, err := NewRun(state, filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("NewRun: %v", err)
+	}
+
+	var got []Event
+	run.Subscribe(func(e Event) { got = append(got, e) })
+
+	start := time.Now()
+	run.Start(start)
+	run.Split(start.Add(12 * time.Second))
+	run.NextComparison()
+	// Setting the active comparison again changes nothing
+	run.SetComparison(ComparisonBestSegments)
+	if s := run.Snapshot(); s.Comparison[0] != 4*time.Second {
+		t.Errorf("delta against best segments = %v, want 4s", s.Comparison[0])
+	}
+	run.NextComparison()
+	if s := run.Snapshot(); s.Comparison[0] != 2*time.Second {
+		t.Errorf("delta against the PB = %v, want 2s", s.Comparison[0])
+	}
+
+	want := []Event{
+		StartedEvent{StartTime: start},
+		SplitEvent{Index: 0, Time: 12 * time.Second, Delta: 2 * time.Second, Gold: false},
+		ComparisonChangedEvent{Comparison: ComparisonBestSegments},
+		ComparisonChangedEvent{Comparison: ComparisonPersonalBest},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
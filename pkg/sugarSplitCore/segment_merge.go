@@ -0,0 +1,191 @@
+package sugarSplitCore
+
+import (
+	"fmt"
+	"time"
+)
+
+// timingMethods lists every timing method a splits file stores
+var timingMethods = []string{TimingRealTime, TimingGameTime}
+
+// field returns the text of a timing method of a history time
+func (t *Time) field(method string) *string {
+	if method == TimingGameTime {
+		return &t.GameTime
+	}
+	return &t.RealTime
+}
+
+// MergeSegmentWithNext joins the segment at index with the one after it,
+// like LiveSplit does when a segment is removed. The merged segment ends at
+// the next segment's split and keeps its name. Every attempt's times of
+// both segments are added up, the gold is recomputed from the merged
+// history and the PB, and the split times at the removed split are dropped.
+func (state *LiveSplitState) MergeSegmentWithNext(index int) error {
+	segments := state.Segments.Segments
+	if index < 0 || index >= len(segments)-1 {
+		return fmt.Errorf("no segment after split %d to merge with", index+1)
+	}
+
+	positions := state.historyPositions()
+	current := segments[index].SegmentHistory.Time
+	for _, t := range current {
+		k, reached := positions[index+1][t.ID]
+		if !reached {
+			// The attempt ended in the next segment, so it didn't reach the
+			// merged one either
+			continue
+		}
+		next := &segments[index+1].SegmentHistory.Time[k]
+
+		for _, method := range timingMethods {
+			d := t.Time(method)
+			if d <= 0 {
+				// A skipped segment's time is already part of the next one
+				continue
+			}
+			if n := next.Time(method); n > 0 {
+				*next.field(method) = formatDurationLSS(d + n)
+				continue
+			}
+
+			// The next split was skipped as well, so the time goes to the
+			// first segment after it that has one
+			for j := index + 2; j < len(segments); j++ {
+				k, ok := positions[j][t.ID]
+				if !ok {
+					break
+				}
+				later := &segments[j].SegmentHistory.Time[k]
+				if l := later.Time(method); l > 0 {
+					*later.field(method) = formatDurationLSS(d + l)
+					break
+				}
+			}
+		}
+	}
+
+	merged := &segments[index+1]
+	for _, method := range timingMethods {
+		gold := state.mergedGold(index, positions, method)
+		if method == TimingGameTime {
+			merged.BestSegmentTime.GameTime = formatOptionalLSS(gold)
+		} else {
+			merged.BestSegmentTime.RealTime = formatOptionalLSS(gold)
+		}
+	}
+
+	state.RemoveSegment(index)
+	return nil
+}
+
+// DeleteSegment removes the split at index without losing its times, like
+// LiveSplit does: the segment is merged with the next one, and the final
+// split's segment with the one before it, which keeps its name.
+func (state *LiveSplitState) DeleteSegment(index int) error {
+	segments := state.Segments.Segments
+	if index < 0 || index >= len(segments) {
+		return fmt.Errorf("no split %d", index+1)
+	}
+	if len(segments) == 1 {
+		return fmt.Errorf("the only split can't be deleted")
+	}
+	if index < len(segments)-1 {
+		return state.MergeSegmentWithNext(index)
+	}
+
+	name, icon := segments[index-1].Name, segments[index-1].Icon
+	if err := state.MergeSegmentWithNext(index - 1); err != nil {
+		return err
+	}
+	merged := &state.Segments.Segments[index-1]
+	merged.Name, merged.Icon = name, icon
+	return nil
+}
+
+// mergedGold returns the best time of the segments at index and index+1
+// together: the fastest attempt through both that didn't skip the split
+// before them, or the PB's. The next gold counts too when the first
+// segment has none, and without anything else the golds of both add up.
+func (state *LiveSplitState) mergedGold(index int, positions []map[string]int, method string) time.Duration {
+	segments := state.Segments.Segments
+
+	var gold time.Duration
+	for _, t := range segments[index+1].SegmentHistory.Time {
+		d := t.Time(method)
+		if d <= 0 {
+			continue
+		}
+		// Attempts that skipped the split before also timed that segment
+		if index > 0 {
+			k, ok := positions[index-1][t.ID]
+			if !ok || segments[index-1].SegmentHistory.Time[k].Time(method) <= 0 {
+				continue
+			}
+		}
+		if gold == 0 || d < gold {
+			gold = d
+		}
+	}
+
+	var start time.Duration
+	if index > 0 {
+		start = ComparisonSplitTimeFor(segments, index-1, ComparisonPersonalBest, method)
+	}
+	if end := ComparisonSplitTimeFor(segments, index+1, ComparisonPersonalBest, method); end > 0 && (index == 0 || start > 0) {
+		if pb := end - start; pb > 0 && (gold == 0 || pb < gold) {
+			gold = pb
+		}
+	}
+
+	first, second := segments[index].BestSegmentTime.Time(method), segments[index+1].BestSegmentTime.Time(method)
+	switch {
+	case first <= 0 && second > 0 && (gold == 0 || second < gold):
+		// A segment that was never timed on its own always had its time
+		// in the next one, so the next gold covers both
+		gold = second
+	case gold == 0 && first > 0 && second > 0:
+		gold = first + second
+	}
+	return gold
+}
+
+// SplitSegment puts a new split named name inside the segment at index, so
+// the segment becomes two. The new segment comes first with no times of its
+// own: the attempts that reached the segment skipped the new split, so the
+// existing times and gold stay right for the pair.
+func (state *LiveSplitState) SplitSegment(index int, name string) error {
+	segments := state.Segments.Segments
+	if index < 0 || index >= len(segments) {
+		return fmt.Errorf("no split %d", index+1)
+	}
+
+	segment := NewSegment(name)
+	for _, t := range segments[index].SegmentHistory.Time {
+		segment.SegmentHistory.Time = append(segment.SegmentHistory.Time, Time{ID: t.ID})
+	}
+
+	state.Segments.Segments = append(segments[:index], append([]Segment{segment}, segments[index:]...)...)
+	return nil
+}
+
+// historyPositions maps every segment's history by attempt ID to the
+// position of the time in it
+func (state *LiveSplitState) historyPositions() []map[string]int {
+	positions := make([]map[string]int, len(state.Segments.Segments))
+	for i, segment := range state.Segments.Segments {
+		positions[i] = make(map[string]int, len(segment.SegmentHistory.Time))
+		for k, t := range segment.SegmentHistory.Time {
+			positions[i][t.ID] = k
+		}
+	}
+	return positions
+}
+
+// formatOptionalLSS formats a time for a splits file, "" when there is none
+func formatOptionalLSS(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return formatDurationLSS(d)
+}
@@ -0,0 +1,185 @@
+package sugarSplitCore
+
+import (
+	"testing"
+	"time"
+)
+
+// setHistory gives the segments one history time per attempt, 0 for a
+// skipped segment and -1 for one the attempt didn't reach
+func setHistory(state *LiveSplitState, attempts map[string][]time.Duration) {
+	for id, times := range attempts {
+		for i, d := range times {
+			if d < 0 {
+				continue
+			}
+			segment := &state.Segments.Segments[i]
+			segment.SegmentHistory.Time = append(segment.SegmentHistory.Time, Time{ID: id, RealTime: formatOptionalLSS(d)})
+		}
+	}
+}
+
+func historyTime(segment Segment, id string) (time.Duration, bool) {
+	for _, t := range segment.SegmentHistory.Time {
+		if t.ID == id {
+			return t.Time(TimingRealTime), true
+		}
+	}
+	return 0, false
+}
+
+func TestMergeSegmentWithNext(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 18 * time.Second, 25 * time.Second},
+	)
+	setHistory(state, map[string][]time.Duration{
+		"1": {10 * time.Second, 20 * time.Second, 30 * time.Second},
+		"2": {9 * time.Second, 22 * time.Second, -1},
+		"3": {11 * time.Second, 0, 40 * time.Second},
+		"4": {12 * time.Second, -1, -1},
+	})
+
+	if err := state.MergeSegmentWithNext(0); err != nil {
+		t.Fatalf("MergeSegmentWithNext: %v", err)
+	}
+	segments := state.Segments.Segments
+	if len(segments) != 2 || segments[0].Name != "Split" {
+		t.Fatalf("merged segments = %d, first %q", len(segments), segments[0].Name)
+	}
+
+	want := map[string]time.Duration{"1": 30 * time.Second, "2": 31 * time.Second, "3": 0}
+	for id, d := range want {
+		if got, ok := historyTime(segments[0], id); !ok || got != d {
+			t.Errorf("attempt %s merged time = %v (%v), want %v", id, got, ok, d)
+		}
+	}
+	if _, ok := historyTime(segments[0], "4"); ok {
+		t.Errorf("attempt 4 reached the merged segment")
+	}
+	// Attempt 3 skipped the merged split, its time moves on to the next segment
+	if got, _ := historyTime(segments[1], "3"); got != 51*time.Second {
+		t.Errorf("attempt 3 next segment = %v, want 51s", got)
+	}
+
+	if got := state.GoldTime(0); got != 30*time.Second {
+		t.Errorf("merged gold = %v, want 30s", got)
+	}
+	if got := state.PBSplitTime(0); got != 30*time.Second {
+		t.Errorf("merged PB split = %v, want 30s", got)
+	}
+
+	if err := state.MergeSegmentWithNext(1); err == nil {
+		t.Errorf("merged the last segment")
+	}
+}
+
+func TestDeleteSegment(t *testing.T) {
+	attempts := map[string][]time.Duration{
+		"1": {10 * time.Second, 20 * time.Second, 30 * time.Second},
+		"2": {11 * time.Second, 0, 40 * time.Second},
+		"3": {0, 31 * time.Second, 29 * time.Second},
+		"4": {12 * time.Second, -1, -1},
+	}
+
+	tests := []struct {
+		name  string
+		index int
+		names []string
+		gold  time.Duration
+	}{
+		{"first split", 0, []string{"B", "C"}, 30 * time.Second},
+		{"middle split", 1, []string{"A", "C"}, 40 * time.Second},
+		{"final split", 2, []string{"A", "B"}, 40 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTimedRun(
+				[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+				[]time.Duration{9 * time.Second, 18 * time.Second, 25 * time.Second},
+			)
+			for i, name := range []string{"A", "B", "C"} {
+				state.Segments.Segments[i].Name = name
+			}
+			setHistory(state, attempts)
+
+			if err := state.DeleteSegment(tt.index); err != nil {
+				t.Fatalf("DeleteSegment: %v", err)
+			}
+			segments := state.Segments.Segments
+			if len(segments) != len(tt.names) {
+				t.Fatalf("%d segments left, want %d", len(segments), len(tt.names))
+			}
+			for i, name := range tt.names {
+				if segments[i].Name != name {
+					t.Errorf("segment %d = %q, want %q", i, segments[i].Name, name)
+				}
+			}
+
+			// Every finished attempt's times still add up to its final time
+			for id, times := range attempts {
+				if times[len(times)-1] < 0 {
+					continue
+				}
+				var want, got time.Duration
+				for _, d := range times {
+					want += d
+				}
+				for _, segment := range segments {
+					d, _ := historyTime(segment, id)
+					got += d
+				}
+				if got != want {
+					t.Errorf("attempt %s adds up to %v, want %v", id, got, want)
+				}
+			}
+
+			if got := state.PBSplitTime(len(segments) - 1); got != 60*time.Second {
+				t.Errorf("final PB split = %v, want 60s", got)
+			}
+			merged := min(tt.index, len(segments)-1)
+			if got := state.GoldTime(merged); got != tt.gold {
+				t.Errorf("merged gold = %v, want %v", got, tt.gold)
+			}
+		})
+	}
+
+	state := newTimedRun([]time.Duration{10 * time.Second}, []time.Duration{9 * time.Second})
+	if err := state.DeleteSegment(0); err == nil {
+		t.Error("deleted the only split")
+	}
+}
+
+func TestSplitSegment(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second},
+		[]time.Duration{9 * time.Second, 18 * time.Second},
+	)
+	setHistory(state, map[string][]time.Duration{"1": {10 * time.Second, 20 * time.Second}})
+	sumOfBest := GetSumOfBest(state.Segments.Segments)
+
+	if err := state.SplitSegment(1, "Halfway"); err != nil {
+		t.Fatalf("SplitSegment: %v", err)
+	}
+	segments := state.Segments.Segments
+	if len(segments) != 3 || segments[1].Name != "Halfway" {
+		t.Fatalf("segments after split = %d, new %q", len(segments), segments[1].Name)
+	}
+	if got, ok := historyTime(segments[1], "1"); !ok || got != 0 {
+		t.Errorf("new segment history = %v (%v), want skipped", got, ok)
+	}
+	if got := GetSumOfBest(segments); got != sumOfBest {
+		t.Errorf("sum of best = %v, want %v", got, sumOfBest)
+	}
+
+	// Merging the new split away brings back the original segment
+	if err := state.MergeSegmentWithNext(1); err != nil {
+		t.Fatalf("MergeSegmentWithNext: %v", err)
+	}
+	if got, _ := historyTime(state.Segments.Segments[1], "1"); got != 20*time.Second {
+		t.Errorf("history after merging back = %v, want 20s", got)
+	}
+	if got := state.GoldTime(1); got != 18*time.Second {
+		t.Errorf("gold after merging back = %v, want 18s", got)
+	}
+}
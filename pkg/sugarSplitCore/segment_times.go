@@ -0,0 +1,155 @@
+package sugarSplitCore
+
+import (
+	"fmt"
+	"time"
+)
+
+// PBSplitTime returns the PB split time of the segment at index, 0 if the PB
+// skipped it or there is none
+func (state *LiveSplitState) PBSplitTime(index int) time.Duration {
+	return ComparisonSplitTime(state.Segments.Segments, index, ComparisonPersonalBest)
+}
+
+// PBSegmentTime returns how long the PB took for the segment at index, 0 if
+// it skipped either end of it
+func (state *LiveSplitState) PBSegmentTime(index int) time.Duration {
+	return comparisonSegmentTime(state.Segments.Segments, index, ComparisonPersonalBest, TimingRealTime)
+}
+
+// GoldTime returns the best segment time of the segment at index
+func (state *LiveSplitState) GoldTime(index int) time.Duration {
+	if index < 0 || index >= len(state.Segments.Segments) {
+		return 0
+	}
+	return state.Segments.Segments[index].BestSegmentTime.Time(TimingRealTime)
+}
+
+// SetPBSplitTime changes the PB split time of the segment at index, 0 clears
+// it like a skipped split. PB split times have to increase from split to
+// split. Golds slower than the PB segments next to the split are lowered to
+// the PB segment time, so the sum of best stays below the PB.
+func (state *LiveSplitState) SetPBSplitTime(index int, split time.Duration) error {
+	if index < 0 || index >= len(state.Segments.Segments) {
+		return fmt.Errorf("no split %d", index+1)
+	}
+	if split < 0 {
+		return fmt.Errorf("split times can't be negative")
+	}
+
+	if split > 0 {
+		if err := state.checkPBOrder(index, split); err != nil {
+			return err
+		}
+	}
+
+	state.setPBSplitTime(index, split)
+	state.fitGold(index)
+	state.fitGold(index + 1)
+	return nil
+}
+
+// SetPBSegmentTime changes how long the PB took for the segment at index.
+// The later PB splits move with it, so their segment times stay the same.
+func (state *LiveSplitState) SetPBSegmentTime(index int, segment time.Duration) error {
+	if index < 0 || index >= len(state.Segments.Segments) {
+		return fmt.Errorf("no split %d", index+1)
+	}
+	if segment <= 0 {
+		return fmt.Errorf("segment times have to be above 0")
+	}
+
+	var start time.Duration
+	if index > 0 {
+		start = state.PBSplitTime(index - 1)
+		if start <= 0 {
+			return fmt.Errorf("the PB has no time for %s", state.Segments.Segments[index-1].Name)
+		}
+	}
+
+	old := state.PBSplitTime(index)
+	split := start + segment
+	if old <= 0 {
+		// Nothing after a skipped split depends on it, it only has to fit
+		return state.SetPBSplitTime(index, split)
+	}
+
+	diff := split - old
+	for i := index; i < len(state.Segments.Segments); i++ {
+		if t := state.PBSplitTime(i); t > 0 {
+			state.setPBSplitTime(i, t+diff)
+		}
+	}
+	state.fitGold(index)
+	return nil
+}
+
+// SetGold changes the best segment time of the segment at index. A gold
+// can't be slower than the PB's segment time.
+func (state *LiveSplitState) SetGold(index int, gold time.Duration) error {
+	if index < 0 || index >= len(state.Segments.Segments) {
+		return fmt.Errorf("no split %d", index+1)
+	}
+	if gold <= 0 {
+		return fmt.Errorf("golds have to be above 0")
+	}
+	if pb := state.PBSegmentTime(index); pb > 0 && gold > pb {
+		return fmt.Errorf("gold can't be slower than the PB segment (%s)", FormatDuration(pb))
+	}
+
+	state.Segments.Segments[index].BestSegmentTime.RealTime = formatDurationLSS(gold)
+	return nil
+}
+
+// checkPBOrder returns an error when a PB split time at index wouldn't be
+// between the PB splits around it
+func (state *LiveSplitState) checkPBOrder(index int, split time.Duration) error {
+	segments := state.Segments.Segments
+	for i := index - 1; i >= 0; i-- {
+		if prev := state.PBSplitTime(i); prev > 0 {
+			if split <= prev {
+				return fmt.Errorf("PB split has to be after %s (%s)", segments[i].Name, FormatDuration(prev))
+			}
+			break
+		}
+	}
+	for i := index + 1; i < len(segments); i++ {
+		if next := state.PBSplitTime(i); next > 0 {
+			if split >= next {
+				return fmt.Errorf("PB split has to be before %s (%s)", segments[i].Name, FormatDuration(next))
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// setPBSplitTime writes a PB split time, adding the PB comparison to the
+// segment if it doesn't have one
+func (state *LiveSplitState) setPBSplitTime(index int, split time.Duration) {
+	segment := &state.Segments.Segments[index]
+
+	value := ""
+	if split > 0 {
+		value = formatDurationLSS(split)
+	}
+	for i := range segment.SplitTimes.SplitTime {
+		if segment.SplitTimes.SplitTime[i].Name == ComparisonPersonalBest {
+			segment.SplitTimes.SplitTime[i].RealTime = value
+			return
+		}
+	}
+	segment.SplitTimes.SplitTime = append(segment.SplitTimes.SplitTime, SplitTime{Name: ComparisonPersonalBest, RealTime: value})
+}
+
+// fitGold lowers the gold of the segment at index to the PB segment time
+// when the PB was faster, or sets it if there's no gold yet
+func (state *LiveSplitState) fitGold(index int) {
+	pb := state.PBSegmentTime(index)
+	if pb <= 0 {
+		return
+	}
+	if gold := state.GoldTime(index); gold <= 0 || gold > pb {
+		state.Segments.Segments[index].BestSegmentTime.RealTime = formatDurationLSS(pb)
+	}
+}
@@ -0,0 +1,86 @@
+package sugarSplitCore
+
+import (
+	"testing"
+	"time"
+)
+
+// newTimedRun returns splits with a PB at the given split times and golds
+func newTimedRun(splits, golds []time.Duration) *LiveSplitState {
+	state := CreateBlankRun("Game", "Any%")
+	for i := 1; i < len(splits); i++ {
+		state.AddSegment(i-1, "Split")
+	}
+	for i := range splits {
+		state.setPBSplitTime(i, splits[i])
+		state.Segments.Segments[i].BestSegmentTime.RealTime = formatDurationLSS(golds[i])
+	}
+	return state
+}
+
+func TestSetPBSplitTime(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 15 * time.Second, 25 * time.Second},
+	)
+
+	for _, split := range []time.Duration{10 * time.Second, 60 * time.Second, -time.Second} {
+		if err := state.SetPBSplitTime(1, split); err == nil {
+			t.Errorf("PB split %v accepted between 10s and 60s", split)
+		}
+	}
+
+	// The first segment gets faster than its gold, the second slower
+	if err := state.SetPBSplitTime(0, 5*time.Second); err != nil {
+		t.Fatalf("SetPBSplitTime: %v", err)
+	}
+	if got := state.GoldTime(0); got != 5*time.Second {
+		t.Errorf("gold 0 = %v, want it lowered to 5s", got)
+	}
+	if got := state.GoldTime(1); got != 15*time.Second {
+		t.Errorf("gold 1 = %v, want 15s kept", got)
+	}
+	if sob, pb := GetSumOfBest(state.Segments.Segments), state.PBSplitTime(2); sob > pb {
+		t.Errorf("sum of best %v above PB %v", sob, pb)
+	}
+}
+
+func TestSetPBSegmentTime(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 15 * time.Second, 25 * time.Second},
+	)
+
+	if err := state.SetPBSegmentTime(1, 14*time.Second); err != nil {
+		t.Fatalf("SetPBSegmentTime: %v", err)
+	}
+	want := []time.Duration{10 * time.Second, 24 * time.Second, 54 * time.Second}
+	for i, split := range want {
+		if got := state.PBSplitTime(i); got != split {
+			t.Errorf("PB split %d = %v, want %v", i, got, split)
+		}
+	}
+	if got := state.PBSegmentTime(2); got != 30*time.Second {
+		t.Errorf("later PB segment = %v, want 30s kept", got)
+	}
+	if got := state.GoldTime(1); got != 14*time.Second {
+		t.Errorf("gold 1 = %v, want 14s", got)
+	}
+}
+
+func TestSetGold(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second},
+		[]time.Duration{9 * time.Second, 15 * time.Second},
+	)
+
+	if err := state.SetGold(1, 21*time.Second); err == nil {
+		t.Errorf("gold slower than the PB segment accepted")
+	}
+	if err := state.SetGold(1, 0); err == nil {
+		t.Errorf("empty gold accepted")
+	}
+	if err := state.SetGold(1, 12*time.Second); err != nil || state.GoldTime(1) != 12*time.Second {
+		t.Errorf("SetGold = %v, gold %v", err, state.GoldTime(1))
+	}
+}
@@ -0,0 +1,106 @@
+package sugarSplitCore
+
+import "time"
+
+// RunSnapshot is an immutable view of a Run at one point in time. The slices
+// are copies owned by the snapshot and State is never modified by the Run
+// once it has been handed out, so a snapshot can be read from any goroutine.
+type RunSnapshot struct {
+	State          *LiveSplitState
+	CurrentSplit   int
+	Splits         []time.Duration
+	IsGold         []bool
+	Comparison     []time.Duration
+	StartTime      time.Time
+	CurrentTime    time.Duration
+	Started        bool
+	Completed      bool
+	Paused         bool
+	ResettingState bool
+	ComparisonName string
+	Hotkeys        []Hotkey
+}
+
+// ComparisonTime returns the split time of the active comparison at the given index
+func (s RunSnapshot) ComparisonTime(splitIndex int) time.Duration {
+	return ComparisonSplitTime(s.State.Segments.Segments, splitIndex, s.ComparisonName)
+}
+
+// GetSegmentTime returns the duration of a specific segment
+func (s RunSnapshot) GetSegmentTime(splitIndex int) time.Duration {
+	if splitIndex < 0 || splitIndex >= len(s.Splits) {
+		return 0
+	}
+
+	if s.Splits[splitIndex] == 0 {
+		return 0
+	}
+
+	if splitIndex == 0 {
+		return s.Splits[0]
+	}
+
+	return s.Splits[splitIndex] - s.Splits[splitIndex-1]
+}
+
+// PreviousSegmentDelta returns the time gained or lost on the last completed
+// segment against the active comparison. After skipped splits the segment
+// starts at the last split that wasn't skipped. It's false if the split was
+// skipped or the comparison has no time for either end of the segment.
+func (s RunSnapshot) PreviousSegmentDelta() (time.Duration, bool) {
+	index := s.CurrentSplit - 1
+	if index < 0 || index >= len(s.Splits) || s.Splits[index] <= 0 {
+		return 0, false
+	}
+
+	end := s.ComparisonTime(index)
+	if end <= 0 {
+		return 0, false
+	}
+	var start time.Duration
+	for i := index - 1; i >= 0; i-- {
+		if s.Splits[i] > 0 {
+			if start = s.ComparisonTime(i); start <= 0 {
+				return 0, false
+			}
+			break
+		}
+	}
+
+	return segmentSince(s.Splits, index) - (end - start), true
+}
+
+// GetPBSegmentTime returns the Personal Best duration for a specific segment
+func (s RunSnapshot) GetPBSegmentTime(splitIndex int) time.Duration {
+	segments := s.State.Segments.Segments
+	if splitIndex < 0 || splitIndex >= len(segments) {
+		return 0
+	}
+
+	segment := segments[splitIndex]
+	if len(segment.SplitTimes.SplitTime) == 0 {
+		return 0
+	}
+
+	pbTime := ParseTime(segment.SplitTimes.SplitTime[0].RealTime)
+
+	if splitIndex == 0 {
+		return pbTime
+	}
+
+	prevPBTime := ParseTime(segments[splitIndex-1].SplitTimes.SplitTime[0].RealTime)
+	return pbTime - prevPBTime
+}
+
+// IsPB checks if the run is a Personal Best
+func (s RunSnapshot) IsPB() bool {
+	segments := s.State.Segments.Segments
+	if len(segments) == 0 || s.CurrentSplit != len(segments) {
+		return false
+	}
+
+	lastSplitTime := s.Splits[len(s.Splits)-1]
+	currentPB := ComparisonSplitTime(segments, len(segments)-1, ComparisonPersonalBest)
+
+	return lastSplitTime > 0 && (lastSplitTime < currentPB || currentPB == 0)
+}
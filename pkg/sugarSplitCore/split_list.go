@@ -0,0 +1,125 @@
+package sugarSplitCore
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// SplitListEntry is a line of a split list: a segment name, optionally with
+// the PB split time and gold to start with
+type SplitListEntry struct {
+	Name    string
+	PBSplit time.Duration
+	Gold    time.Duration
+}
+
+// ParseSplitList reads a list of split names, one per line. Times can follow
+// a name in columns separated by tabs (as pasted from a spreadsheet) or "|":
+// the PB split time, then the gold. Blank lines and lines starting with #
+// are skipped.
+func ParseSplitList(text string) ([]SplitListEntry, error) {
+	var entries []SplitListEntry
+	var lastPB time.Duration
+
+	for number, line := range strings.Split(text, "\n") {
+		// Leading tabs are an empty name column, not indentation
+		line = strings.TrimRight(strings.TrimLeft(line, " "), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Empty columns are kept, so a gold can follow a missing PB time
+		columns := strings.Split(strings.ReplaceAll(line, "|", "\t"), "\t")
+		if len(columns) > 3 {
+			return nil, fmt.Errorf("line %d: too many columns (name, PB split time, gold)", number+1)
+		}
+
+		entry := SplitListEntry{Name: strings.TrimSpace(columns[0])}
+		if entry.Name == "" {
+			return nil, fmt.Errorf("line %d: split has no name", number+1)
+		}
+		for i, column := range columns[1:] {
+			column = strings.TrimSpace(column)
+			if column == "" || column == "-" {
+				continue
+			}
+			t, err := ParseDuration(column)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", number+1, err)
+			}
+			if t <= 0 {
+				return nil, fmt.Errorf("line %d: times have to be above 0", number+1)
+			}
+			if i == 0 {
+				entry.PBSplit = t
+			} else {
+				entry.Gold = t
+			}
+		}
+
+		if entry.PBSplit > 0 {
+			if entry.PBSplit <= lastPB {
+				return nil, fmt.Errorf("line %d: PB split time %s isn't after the one before it", number+1, FormatDuration(entry.PBSplit))
+			}
+			lastPB = entry.PBSplit
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("split list has no splits")
+	}
+	return entries, nil
+}
+
+// LoadSplitList reads a split list file, see ParseSplitList
+func LoadSplitList(path string) ([]SplitListEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading split list: %v", err)
+	}
+	return ParseSplitList(string(data))
+}
+
+// ReadSplitList reads a split list typed or pasted by the user. A single
+// line is the path of a split list file if there is one, anything else is
+// the list itself.
+func ReadSplitList(input string) ([]SplitListEntry, error) {
+	if path := strings.TrimSpace(input); !strings.Contains(path, "\n") {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return LoadSplitList(path)
+		}
+	}
+	return ParseSplitList(input)
+}
+
+// SetSegmentsFromList replaces the segments with the ones of a split list.
+// Segments without a gold get their PB segment time as one. The attempt
+// history is cleared too, it belongs to the old segments.
+func (state *LiveSplitState) SetSegmentsFromList(entries []SplitListEntry) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("split list has no splits")
+	}
+
+	list := &LiveSplitState{}
+	for i, entry := range entries {
+		list.Segments.Segments = append(list.Segments.Segments, NewSegment(entry.Name))
+		if entry.PBSplit > 0 {
+			list.setPBSplitTime(i, entry.PBSplit)
+		}
+	}
+	for i, entry := range entries {
+		if entry.Gold > 0 {
+			if err := list.SetGold(i, entry.Gold); err != nil {
+				return fmt.Errorf("%s: %v", entry.Name, err)
+			}
+		}
+		list.fitGold(i)
+	}
+
+	state.Segments = list.Segments
+	state.AttemptHistory.Attempt = nil
+	return nil
+}
@@ -0,0 +1,104 @@
+package sugarSplitCore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseSplitList(t *testing.T) {
+	entries, err := ParseSplitList("# route\nLevel 1\t0:30\t0:28\n\nLevel 2 | | 0:40\nLevel 3|1:45.5\n")
+	if err != nil {
+		t.Fatalf("ParseSplitList: %v", err)
+	}
+	want := []SplitListEntry{
+		{Name: "Level 1", PBSplit: 30 * time.Second, Gold: 28 * time.Second},
+		{Name: "Level 2", Gold: 40 * time.Second},
+		{Name: "Level 3", PBSplit: 105500 * time.Millisecond},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	for _, text := range []string{"", "# nothing", "A\t1:00\nB\t0:59", "A\tsoon", "A\t1\t2\t3", "\t1:00"} {
+		if _, err := ParseSplitList(text); err == nil {
+			t.Errorf("ParseSplitList(%q) succeeded", text)
+		}
+	}
+}
+
+func TestReadSplitList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "route.txt")
+	if err := os.WriteFile(path, []byte("From file\nSecond\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"file", "  " + path + "\n", []string{"From file", "Second"}},
+		{"single pasted line", "Only split | 1:00", []string{"Only split"}},
+		{"directory", filepath.Dir(path), []string{filepath.Dir(path)}},
+		{"pasted lines", "A\nB\n", []string{"A", "B"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadSplitList(tt.input)
+			if err != nil {
+				t.Fatalf("ReadSplitList: %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.want))
+			}
+			for i, name := range tt.want {
+				if entries[i].Name != name {
+					t.Errorf("entry %d = %q, want %q", i, entries[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestSetSegmentsFromList(t *testing.T) {
+	state := CreateBlankRun("Game", "Any%")
+	state.AttemptHistory.Attempt = []Attempt{{ID: "1", RealTime: "00:01:00.0000000"}}
+	err := state.SetSegmentsFromList([]SplitListEntry{
+		{Name: "A", PBSplit: 30 * time.Second, Gold: 28 * time.Second},
+		{Name: "B", PBSplit: 70 * time.Second},
+		{Name: "C"},
+	})
+	if err != nil {
+		t.Fatalf("SetSegmentsFromList: %v", err)
+	}
+
+	if n := len(state.Segments.Segments); n != 3 {
+		t.Fatalf("%d segments, want 3", n)
+	}
+	if got := state.GoldTime(0); got != 28*time.Second {
+		t.Errorf("gold A = %v, want 28s", got)
+	}
+	if got := state.GoldTime(1); got != 40*time.Second {
+		t.Errorf("gold B = %v, want the PB segment 40s", got)
+	}
+	if got := state.GoldTime(2); got != 0 {
+		t.Errorf("gold C = %v, want none", got)
+	}
+	if n := len(state.AttemptHistory.Attempt); n != 0 {
+		t.Errorf("%d attempts kept from the old segments, want none", n)
+	}
+
+	if err := state.SetSegmentsFromList([]SplitListEntry{{Name: "A", PBSplit: 30 * time.Second, Gold: 31 * time.Second}}); err == nil {
+		t.Errorf("gold slower than the PB segment accepted")
+	}
+	if n := len(state.Segments.Segments); n != 3 {
+		t.Errorf("failed import changed the segments")
+	}
+}
@@ -0,0 +1,231 @@
+package sugarSplitCore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SplitsIOSchemaVersion is the version of the splits.io Exchange Format that
+// is read and written
+const SplitsIOSchemaVersion = "v1.0.0"
+
+// splits.io Exchange Format structures, the JSON every timer on splits.io
+// converts to and from. Times are whole milliseconds.
+type splitsIORun struct {
+	SchemaVersion string            `json:"_schemaVersion"`
+	Links         *splitsIORunLinks `json:"links,omitempty"`
+	Timer         splitsIOTimer     `json:"timer"`
+	Attempts      splitsIOAttempts  `json:"attempts"`
+	Game          *splitsIONamed    `json:"game,omitempty"`
+	Category      *splitsIONamed    `json:"category,omitempty"`
+	Segments      []splitsIOSegment `json:"segments"`
+}
+
+type splitsIORunLinks struct {
+	SpeedrunComID string `json:"speedruncomId,omitempty"`
+}
+
+type splitsIOTimer struct {
+	Shortname string `json:"shortname"`
+	Longname  string `json:"longname"`
+	Website   string `json:"website,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+type splitsIONamed struct {
+	Longname string `json:"longname"`
+}
+
+type splitsIOAttempts struct {
+	Total     int                      `json:"total"`
+	Histories []splitsIOAttemptHistory `json:"histories,omitempty"`
+}
+
+type splitsIOAttemptHistory struct {
+	AttemptNumber int     `json:"attemptNumber"`
+	RealtimeMS    int64   `json:"realtimeMS,omitempty"`
+	GametimeMS    int64   `json:"gametimeMS,omitempty"`
+	StartedAt     *string `json:"startedAt,omitempty"`
+	EndedAt       *string `json:"endedAt,omitempty"`
+}
+
+type splitsIODuration struct {
+	RealtimeMS int64 `json:"realtimeMS,omitempty"`
+	GametimeMS int64 `json:"gametimeMS,omitempty"`
+}
+
+type splitsIOSegment struct {
+	Name         string                   `json:"name"`
+	EndedAt      *splitsIODuration        `json:"endedAt,omitempty"`
+	BestDuration *splitsIODuration        `json:"bestDuration,omitempty"`
+	IsSkipped    bool                     `json:"isSkipped"`
+	Histories    []splitsIOSegmentHistory `json:"histories,omitempty"`
+}
+
+type splitsIOSegmentHistory struct {
+	AttemptNumber int   `json:"attemptNumber"`
+	RealtimeMS    int64 `json:"realtimeMS,omitempty"`
+	GametimeMS    int64 `json:"gametimeMS,omitempty"`
+	IsSkipped     bool  `json:"isSkipped"`
+}
+
+// ExportSplitsIO converts splits to the splits.io Exchange Format. The PB,
+// golds, attempt history and segment history are kept for both timing
+// methods. Attempts with IDs that aren't numbers are left out.
+func ExportSplitsIO(state *LiveSplitState) ([]byte, error) {
+	run := splitsIORun{
+		SchemaVersion: SplitsIOSchemaVersion,
+		Timer: splitsIOTimer{
+			Shortname: "sugarsplit",
+			Longname:  "sugarSplit",
+			Website:   "https://github.com/micr0-dev/sugarSplit",
+		},
+		Attempts: splitsIOAttempts{Total: state.AttemptCount},
+		Game:     &splitsIONamed{Longname: state.GameName},
+		Category: &splitsIONamed{Longname: state.CategoryName},
+	}
+	if state.Metadata.Run.ID != "" {
+		run.Links = &splitsIORunLinks{SpeedrunComID: state.Metadata.Run.ID}
+	}
+
+	for _, attempt := range state.AttemptHistory.Attempt {
+		number, err := strconv.Atoi(attempt.ID)
+		if err != nil {
+			continue
+		}
+		history := splitsIOAttemptHistory{
+			AttemptNumber: number,
+			RealtimeMS:    ParseTime(attempt.RealTime).Milliseconds(),
+			GametimeMS:    ParseTime(attempt.GameTime).Milliseconds(),
+			StartedAt:     splitsIOTimestamp(attempt.Started),
+			EndedAt:       splitsIOTimestamp(attempt.Ended),
+		}
+		run.Attempts.Histories = append(run.Attempts.Histories, history)
+	}
+
+	for i, segment := range state.Segments.Segments {
+		exported := splitsIOSegment{Name: segment.Name}
+
+		realTime := ComparisonSplitTimeFor(state.Segments.Segments, i, ComparisonPersonalBest, TimingRealTime)
+		gameTime := ComparisonSplitTimeFor(state.Segments.Segments, i, ComparisonPersonalBest, TimingGameTime)
+		exported.EndedAt = splitsIODurationOf(realTime, gameTime)
+		exported.IsSkipped = realTime <= 0 && gameTime <= 0
+		exported.BestDuration = splitsIODurationOf(segment.BestSegmentTime.Time(TimingRealTime), segment.BestSegmentTime.Time(TimingGameTime))
+
+		for _, t := range segment.SegmentHistory.Time {
+			number, err := strconv.Atoi(t.ID)
+			if err != nil {
+				continue
+			}
+			exported.Histories = append(exported.Histories, splitsIOSegmentHistory{
+				AttemptNumber: number,
+				RealtimeMS:    t.Time(TimingRealTime).Milliseconds(),
+				GametimeMS:    t.Time(TimingGameTime).Milliseconds(),
+				IsSkipped:     t.RealTime == "" && t.GameTime == "",
+			})
+		}
+		run.Segments = append(run.Segments, exported)
+	}
+
+	return json.MarshalIndent(run, "", "  ")
+}
+
+// ImportSplitsIO reads splits from the splits.io Exchange Format
+func ImportSplitsIO(data []byte) (*LiveSplitState, error) {
+	var run splitsIORun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("error parsing splits.io run: %v", err)
+	}
+	if run.SchemaVersion != SplitsIOSchemaVersion {
+		return nil, fmt.Errorf("unsupported splits.io schema version %q (supported: %s)", run.SchemaVersion, SplitsIOSchemaVersion)
+	}
+	if len(run.Segments) == 0 {
+		return nil, fmt.Errorf("splits.io run has no segments")
+	}
+
+	state := CreateBlankRun("", "")
+	if run.Game != nil {
+		state.GameName = run.Game.Longname
+	}
+	if run.Category != nil {
+		state.CategoryName = run.Category.Longname
+	}
+	if run.Links != nil {
+		state.Metadata.Run.ID = run.Links.SpeedrunComID
+	}
+
+	for _, history := range run.Attempts.Histories {
+		state.AttemptHistory.Attempt = append(state.AttemptHistory.Attempt, Attempt{
+			ID:              strconv.Itoa(history.AttemptNumber),
+			Started:         attemptTimestamp(history.StartedAt),
+			IsStartedSynced: "False",
+			Ended:           attemptTimestamp(history.EndedAt),
+			IsEndedSynced:   "False",
+			RealTime:        formatMS(history.RealtimeMS),
+			GameTime:        formatMS(history.GametimeMS),
+		})
+	}
+	state.AttemptCount = max(run.Attempts.Total, len(state.AttemptHistory.Attempt))
+
+	state.Segments.Segments = nil
+	for _, imported := range run.Segments {
+		segment := NewSegment(imported.Name)
+		if imported.EndedAt != nil && !imported.IsSkipped {
+			segment.SplitTimes.SplitTime[0].RealTime = formatMS(imported.EndedAt.RealtimeMS)
+			segment.SplitTimes.SplitTime[0].GameTime = formatMS(imported.EndedAt.GametimeMS)
+		}
+		if imported.BestDuration != nil {
+			segment.BestSegmentTime.RealTime = formatMS(imported.BestDuration.RealtimeMS)
+			segment.BestSegmentTime.GameTime = formatMS(imported.BestDuration.GametimeMS)
+		}
+		for _, history := range imported.Histories {
+			t := Time{ID: strconv.Itoa(history.AttemptNumber)}
+			if !history.IsSkipped {
+				t.RealTime = formatMS(history.RealtimeMS)
+				t.GameTime = formatMS(history.GametimeMS)
+			}
+			segment.SegmentHistory.Time = append(segment.SegmentHistory.Time, t)
+		}
+		state.Segments.Segments = append(state.Segments.Segments, segment)
+	}
+
+	return state, nil
+}
+
+// splitsIODurationOf returns the times of both timing methods, nil if
+// there are none
+func splitsIODurationOf(realTime, gameTime time.Duration) *splitsIODuration {
+	if realTime <= 0 && gameTime <= 0 {
+		return nil
+	}
+	return &splitsIODuration{RealtimeMS: max(realTime, 0).Milliseconds(), GametimeMS: max(gameTime, 0).Milliseconds()}
+}
+
+// splitsIOTimestamp converts an attempt timestamp to RFC 3339
+func splitsIOTimestamp(timestamp string) *string {
+	t, err := time.Parse(AttemptTimeLayout, timestamp)
+	if err != nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
+
+// attemptTimestamp converts an RFC 3339 timestamp to an attempt timestamp
+func attemptTimestamp(timestamp *string) string {
+	if timestamp == nil {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, *timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(AttemptTimeLayout)
+}
+
+// formatMS formats milliseconds for a splits file, "" for none
+func formatMS(ms int64) string {
+	return formatOptionalLSS(time.Duration(ms) * time.Millisecond)
+}
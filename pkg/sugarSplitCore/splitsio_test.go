@@ -0,0 +1,80 @@
+package sugarSplitCore
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitsIORoundTrip(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 0, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 15 * time.Second, 25 * time.Second},
+	)
+	state.Segments.Segments[2].BestSegmentTime.GameTime = formatDurationLSS(24 * time.Second)
+	state.Metadata.Run.ID = "abc123"
+	state.AttemptCount = 3
+	state.AttemptHistory.Attempt = []Attempt{
+		{ID: "1", Started: "01/02/2024 15:04:05", Ended: "01/02/2024 15:05:05", RealTime: formatDurationLSS(60 * time.Second)},
+		{ID: "2", Started: "01/03/2024 10:00:00", Ended: "01/03/2024 10:00:20"},
+	}
+	setHistory(state, map[string][]time.Duration{
+		"1": {10 * time.Second, 0, 50 * time.Second},
+		"2": {12 * time.Second, -1, -1},
+	})
+
+	data, err := ExportSplitsIO(state)
+	if err != nil {
+		t.Fatalf("ExportSplitsIO: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil || raw["_schemaVersion"] != SplitsIOSchemaVersion {
+		t.Fatalf("exported JSON has schema %v (%v)", raw["_schemaVersion"], err)
+	}
+
+	imported, err := ImportSplitsIO(data)
+	if err != nil {
+		t.Fatalf("ImportSplitsIO: %v", err)
+	}
+
+	if imported.GameName != state.GameName || imported.CategoryName != state.CategoryName || imported.Metadata.Run.ID != "abc123" {
+		t.Errorf("run info = %q %q %q", imported.GameName, imported.CategoryName, imported.Metadata.Run.ID)
+	}
+	if imported.AttemptCount != 3 {
+		t.Errorf("attempt count = %d, want 3", imported.AttemptCount)
+	}
+	for i, attempt := range state.AttemptHistory.Attempt {
+		got := imported.AttemptHistory.Attempt[i]
+		if got.ID != attempt.ID || got.Started != attempt.Started || got.Ended != attempt.Ended || ParseTime(got.RealTime) != ParseTime(attempt.RealTime) {
+			t.Errorf("attempt %d = %+v, want %+v", i, got, attempt)
+		}
+	}
+
+	for i, segment := range state.Segments.Segments {
+		got := imported.Segments.Segments[i]
+		if got.Name != segment.Name {
+			t.Errorf("segment %d name = %q", i, got.Name)
+		}
+		for _, method := range timingMethods {
+			if a, b := ComparisonSplitTimeFor(imported.Segments.Segments, i, ComparisonPersonalBest, method), ComparisonSplitTimeFor(state.Segments.Segments, i, ComparisonPersonalBest, method); a != b {
+				t.Errorf("segment %d %s PB = %v, want %v", i, method, a, b)
+			}
+			if a, b := got.BestSegmentTime.Time(method), segment.BestSegmentTime.Time(method); a != b {
+				t.Errorf("segment %d %s gold = %v, want %v", i, method, a, b)
+			}
+		}
+		if !reflect.DeepEqual(imported.segmentHistoryIndex()[i], state.segmentHistoryIndex()[i]) {
+			t.Errorf("segment %d history = %v, want %v", i, got.SegmentHistory.Time, segment.SegmentHistory.Time)
+		}
+	}
+}
+
+func TestImportSplitsIORejectsOtherSchemas(t *testing.T) {
+	if _, err := ImportSplitsIO([]byte(`{"_schemaVersion": "v2.0.0", "segments": [{"name": "A"}]}`)); err == nil {
+		t.Errorf("unknown schema version accepted")
+	}
+	if _, err := ImportSplitsIO([]byte(`{"_schemaVersion": "v1.0.0", "segments": []}`)); err == nil {
+		t.Errorf("run without segments accepted")
+	}
+}
@@ -0,0 +1,181 @@
+package sugarSplitCore
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SegmentStats summarizes the recorded times of one segment
+type SegmentStats struct {
+	Name    string
+	Gold    time.Duration
+	Average time.Duration
+	Median  time.Duration
+	StdDev  time.Duration
+	Worst   time.Duration
+	// Samples is the number of segment times the statistics are based on
+	Samples int
+	// Attempts is the number of attempts that reached the segment
+	Attempts int
+	// Resets is the number of attempts that ended during the segment
+	Resets int
+	// Consistency is 100% minus the coefficient of variation of the segment
+	// times, so 100 means every attempt took exactly as long
+	Consistency float64
+}
+
+// SegmentSamples returns the recorded times of every segment. Times that
+// follow a skipped segment cover more than one segment and are left out.
+func (state *LiveSplitState) SegmentSamples() [][]time.Duration {
+	history := state.segmentHistoryIndex()
+	samples := make([][]time.Duration, len(state.Segments.Segments))
+
+	for i, segment := range state.Segments.Segments {
+		for _, t := range segment.SegmentHistory.Time {
+			if t.RealTime == "" {
+				continue
+			}
+			if i > 0 {
+				if prev, ok := history[i-1][t.ID]; ok && prev.RealTime == "" {
+					continue
+				}
+			}
+			if d := ParseTime(t.RealTime); d > 0 {
+				samples[i] = append(samples[i], d)
+			}
+		}
+	}
+
+	return samples
+}
+
+// SegmentStatistics calculates statistics for every segment from its history
+func (state *LiveSplitState) SegmentStatistics() []SegmentStats {
+	samples := state.SegmentSamples()
+	attempts := state.AttemptSummaries()
+	stats := make([]SegmentStats, len(state.Segments.Segments))
+
+	for i, segment := range state.Segments.Segments {
+		s := SegmentStats{
+			Name:    segment.Name,
+			Gold:    ParseTime(segment.BestSegmentTime.RealTime),
+			Samples: len(samples[i]),
+		}
+
+		for _, attempt := range attempts {
+			if attempt.Reached >= i {
+				s.Attempts++
+			}
+			if !attempt.Finished && attempt.Reached == i {
+				s.Resets++
+			}
+		}
+
+		if len(samples[i]) > 0 {
+			sorted := append([]time.Duration(nil), samples[i]...)
+			sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+
+			var sum float64
+			for _, d := range sorted {
+				sum += float64(d)
+			}
+			mean := sum / float64(len(sorted))
+
+			var variance float64
+			for _, d := range sorted {
+				variance += (float64(d) - mean) * (float64(d) - mean)
+			}
+			stdDev := math.Sqrt(variance / float64(len(sorted)))
+
+			if s.Gold == 0 {
+				s.Gold = sorted[0]
+			}
+			s.Average = time.Duration(mean)
+			s.Median = median(sorted)
+			s.StdDev = time.Duration(stdDev)
+			s.Worst = sorted[len(sorted)-1]
+			s.Consistency = math.Max(0, 100*(1-stdDev/mean))
+		}
+
+		stats[i] = s
+	}
+
+	return stats
+}
+
+// median returns the median of a sorted, non-empty slice
+func median(sorted []time.Duration) time.Duration {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// ResetPoint describes how many attempts ended at one split
+type ResetPoint struct {
+	Name string
+	// Reached is the number of attempts that started the segment
+	Reached int
+	// Resets is the number of attempts that ended during the segment
+	Resets int
+	// ResetRate is the share of attempts reaching the segment that ended in it
+	ResetRate float64
+	// Survival is the share of all attempts that got through the segment
+	Survival float64
+}
+
+// ResetAnalysis calculates the reset rate and survival curve of every split
+func (state *LiveSplitState) ResetAnalysis() []ResetPoint {
+	attempts := state.AttemptSummaries()
+	points := make([]ResetPoint, len(state.Segments.Segments))
+
+	for i, segment := range state.Segments.Segments {
+		p := ResetPoint{Name: segment.Name}
+
+		survived := 0
+		for _, attempt := range attempts {
+			if attempt.Reached >= i {
+				p.Reached++
+			}
+			if attempt.Reached > i {
+				survived++
+			} else if !attempt.Finished && attempt.Reached == i {
+				p.Resets++
+			}
+		}
+
+		if p.Reached > 0 {
+			p.ResetRate = float64(p.Resets) / float64(p.Reached)
+		}
+		if len(attempts) > 0 {
+			p.Survival = float64(survived) / float64(len(attempts))
+		}
+
+		points[i] = p
+	}
+
+	return points
+}
+
+// WriteResetCSV writes a reset analysis as CSV, a row per split
+func WriteResetCSV(w io.Writer, points []ResetPoint) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"split", "name", "reached", "resets", "reset_rate", "survival"})
+	for i, point := range points {
+		out.Write([]string{
+			strconv.Itoa(i + 1),
+			point.Name,
+			strconv.Itoa(point.Reached),
+			strconv.Itoa(point.Resets),
+			strconv.FormatFloat(point.ResetRate, 'f', 4, 64),
+			strconv.FormatFloat(point.Survival, 'f', 4, 64),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
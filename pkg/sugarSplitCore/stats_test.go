@@ -0,0 +1,140 @@
+package sugarSplitCore
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newStatsRun returns three segments with five attempts: two finished, one
+// of them skipping the second split, and three reset in each segment
+func newStatsRun() *LiveSplitState {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 0, 0},
+	)
+	setHistory(state, map[string][]time.Duration{
+		"1": {10 * time.Second, 20 * time.Second, 30 * time.Second},
+		"2": {12 * time.Second, 0, 40 * time.Second},
+		"3": {14 * time.Second, 24 * time.Second, -1},
+		"4": {16 * time.Second, -1, -1},
+		"5": {-1, -1, -1},
+	})
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		state.AttemptHistory.Attempt = append(state.AttemptHistory.Attempt, Attempt{ID: id})
+	}
+	return state
+}
+
+func TestSegmentStatistics(t *testing.T) {
+	stats := newStatsRun().SegmentStatistics()
+
+	tests := []struct {
+		name        string
+		want        SegmentStats
+		consistency float64
+	}{
+		{
+			// A recorded gold is kept even when no sample is that fast
+			name: "first segment",
+			want: SegmentStats{
+				Gold: 9 * time.Second, Average: 13 * time.Second, Median: 13 * time.Second,
+				StdDev: time.Duration(math.Sqrt(5) * float64(time.Second)), Worst: 16 * time.Second,
+				Samples: 4, Attempts: 5, Resets: 1,
+			},
+			consistency: 100 * (1 - math.Sqrt(5)/13),
+		},
+		{
+			// The skipped entry isn't a sample and the median of two averages
+			name: "segment with a skip",
+			want: SegmentStats{
+				Gold: 20 * time.Second, Average: 22 * time.Second, Median: 22 * time.Second,
+				StdDev: 2 * time.Second, Worst: 24 * time.Second,
+				Samples: 2, Attempts: 4, Resets: 1,
+			},
+			consistency: 100 * (1 - 2.0/22),
+		},
+		{
+			// The time after the skip covers two segments and is left out
+			name: "segment after a skip",
+			want: SegmentStats{
+				Gold: 30 * time.Second, Average: 30 * time.Second, Median: 30 * time.Second,
+				Worst: 30 * time.Second, Samples: 1, Attempts: 3, Resets: 1,
+			},
+			consistency: 100,
+		},
+	}
+
+	if len(stats) != len(tests) {
+		t.Fatalf("got %d segments, want %d", len(stats), len(tests))
+	}
+	for i, tt := range tests {
+		got := stats[i]
+		if math.Abs(got.Consistency-tt.consistency) > 1e-6 {
+			t.Errorf("%s: consistency = %f, want %f", tt.name, got.Consistency, tt.consistency)
+		}
+		got.Name, got.Consistency = "", 0
+		if diff := got.StdDev - tt.want.StdDev; diff < -time.Microsecond || diff > time.Microsecond {
+			t.Errorf("%s: standard deviation = %v, want %v", tt.name, got.StdDev, tt.want.StdDev)
+		}
+		got.StdDev = tt.want.StdDev
+		if got != tt.want {
+			t.Errorf("%s: stats = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentStatisticsWithoutHistory(t *testing.T) {
+	state := newTimedRun([]time.Duration{10 * time.Second}, []time.Duration{8 * time.Second})
+
+	stats := state.SegmentStatistics()
+	want := SegmentStats{Name: "Split 1", Gold: 8 * time.Second}
+	if len(stats) != 1 || stats[0] != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestResetAnalysisCSV(t *testing.T) {
+	state := newTimedRun(
+		[]time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second},
+		[]time.Duration{9 * time.Second, 18 * time.Second, 25 * time.Second},
+	)
+	state.Segments.Segments[0].Name = "Forest, part 1"
+	state.Segments.Segments[1].Name = "Castle"
+	state.Segments.Segments[2].Name = "Boss"
+	setHistory(state, map[string][]time.Duration{
+		// Finished
+		"1": {10 * time.Second, 20 * time.Second, 30 * time.Second},
+		// Reset during the boss
+		"2": {11 * time.Second, 21 * time.Second, -1},
+		// Reset before the first split
+		"3": {-1, -1, -1},
+		// Reset during the castle
+		"4": {12 * time.Second, -1, -1},
+	})
+	for _, id := range []string{"1", "2", "3", "4"} {
+		state.AttemptHistory.Attempt = append(state.AttemptHistory.Attempt, Attempt{ID: id})
+	}
+
+	var out strings.Builder
+	if err := WriteResetCSV(&out, state.ResetAnalysis()); err != nil {
+		t.Fatalf("WriteResetCSV: %v", err)
+	}
+
+	want := []string{
+		"split,name,reached,resets,reset_rate,survival",
+		`1,"Forest, part 1",4,1,0.2500,0.7500`,
+		"2,Castle,3,1,0.3333,0.5000",
+		"3,Boss,2,1,0.5000,0.2500",
+	}
+	got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(got), len(want), out.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package sugarSplitCore
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Color profiles a theme can force instead of detecting the terminal's
+const (
+	ColorProfileAuto      = "auto"
+	ColorProfileTrueColor = "truecolor"
+	ColorProfile256       = "256"
+	ColorProfile16        = "16"
+)
+
+// ThemeColor is one color of a theme with fallbacks for terminals that
+// support fewer colors. Empty fallbacks are converted from the other values.
+type ThemeColor struct {
+	TrueColor string
+	ANSI256   string
+	ANSI      string
+}
+
+// Theme holds the colors used by the interface
+type Theme struct {
+	AheadGaining  ThemeColor
+	AheadLosing   ThemeColor
+	BehindGaining ThemeColor
+	BehindLosing  ThemeColor
+	Gold          ThemeColor
+	PB            ThemeColor
+	Highlight     ThemeColor
+	Text          ThemeColor
+	Title         ThemeColor
+	Muted         ThemeColor
+	ColorProfile  string
+}
+
+// ThemeConfig is the [theme] section of the config file. It picks a bundled
+// theme and overrides single colors of it with hex ("#00cc36") or ANSI
+// ("82") values.
+type ThemeConfig struct {
+	Name          string `toml:"name"`
+	ColorProfile  string `toml:"color_profile"`
+	AheadGaining  string `toml:"ahead_gaining"`
+	AheadLosing   string `toml:"ahead_losing"`
+	BehindGaining string `toml:"behind_gaining"`
+	BehindLosing  string `toml:"behind_losing"`
+	Gold          string `toml:"gold"`
+	PB            string `toml:"pb"`
+	Highlight     string `toml:"highlight"`
+	Text          string `toml:"text"`
+	Title         string `toml:"title"`
+	Muted         string `toml:"muted"`
+}
+
+// DefaultTheme is the name of the theme used when none is configured
+const DefaultTheme = "default"
+
+// BundledThemes are the themes that can be picked by name
+var BundledThemes = map[string]Theme{
+	"default": {
+		AheadGaining:  ThemeColor{"#5fff00", "82", "10"},
+		AheadLosing:   ThemeColor{"#87ff87", "120", "2"},
+		BehindGaining: ThemeColor{"#ff8787", "210", "1"},
+		BehindLosing:  ThemeColor{"#ff0000", "196", "9"},
+		Gold:          ThemeColor{"#ffd700", "220", "11"},
+		PB:            ThemeColor{"#00afff", "39", "12"},
+		Highlight:     ThemeColor{"#00005f", "17", "4"},
+		Title:         ThemeColor{"#ff5faf", "205", "13"},
+		Muted:         ThemeColor{"#808080", "244", "8"},
+	},
+	"livesplit": {
+		AheadGaining:  ThemeColor{"#00cc36", "35", "10"},
+		AheadLosing:   ThemeColor{"#52cc73", "71", "2"},
+		BehindGaining: ThemeColor{"#cc5c52", "167", "1"},
+		BehindLosing:  ThemeColor{"#cc1200", "160", "9"},
+		Gold:          ThemeColor{"#d8af1f", "178", "11"},
+		PB:            ThemeColor{"#16a6ff", "33", "12"},
+		Highlight:     ThemeColor{"#153574", "18", "4"},
+		Text:          ThemeColor{"#ffffff", "231", "15"},
+		Title:         ThemeColor{"#ffffff", "231", "15"},
+		Muted:         ThemeColor{"#acacac", "145", "7"},
+	},
+	"high_contrast": {
+		AheadGaining:  ThemeColor{"#00ff00", "46", "10"},
+		AheadLosing:   ThemeColor{"#afffaf", "157", "2"},
+		BehindGaining: ThemeColor{"#ffafaf", "217", "1"},
+		BehindLosing:  ThemeColor{"#ff0000", "196", "9"},
+		Gold:          ThemeColor{"#ffff00", "226", "11"},
+		PB:            ThemeColor{"#00ffff", "51", "14"},
+		Highlight:     ThemeColor{"#0000af", "19", "4"},
+		Text:          ThemeColor{"#ffffff", "231", "15"},
+		Title:         ThemeColor{"#ffffff", "231", "15"},
+		Muted:         ThemeColor{"#d0d0d0", "252", "7"},
+	},
+	// Okabe-Ito palette: blue for ahead and orange for behind can be told
+	// apart with every common kind of color blindness
+	"colorblind": {
+		AheadGaining:  ThemeColor{"#0072b2", "25", "4"},
+		AheadLosing:   ThemeColor{"#56b4e9", "74", "12"},
+		BehindGaining: ThemeColor{"#e69f00", "214", "3"},
+		BehindLosing:  ThemeColor{"#d55e00", "166", "9"},
+		Gold:          ThemeColor{"#f0e442", "227", "11"},
+		PB:            ThemeColor{"#cc79a7", "175", "5"},
+		Highlight:     ThemeColor{"#303030", "236", "0"},
+		Title:         ThemeColor{"#009e73", "36", "6"},
+		Muted:         ThemeColor{"#808080", "244", "8"},
+	},
+	// Shades of gray only. 16 color terminals have just three visible ones,
+	// so there the colors can't all differ.
+	"monochrome": {
+		AheadGaining:  ThemeColor{"#ffffff", "231", "15"},
+		AheadLosing:   ThemeColor{"#bcbcbc", "250", "7"},
+		BehindGaining: ThemeColor{"#8a8a8a", "245", "8"},
+		BehindLosing:  ThemeColor{"#585858", "240", "8"},
+		Gold:          ThemeColor{"#eeeeee", "255", "15"},
+		PB:            ThemeColor{"#dadada", "253", "15"},
+		Highlight:     ThemeColor{"#303030", "236", "0"},
+		Text:          ThemeColor{"#d0d0d0", "252", "7"},
+		Title:         ThemeColor{"#e4e4e4", "254", "15"},
+		Muted:         ThemeColor{"#6c6c6c", "242", "8"},
+	},
+}
+
+// ThemeNames returns the names of the bundled themes in alphabetical order
+func ThemeNames() []string {
+	names := make([]string, 0, len(BundledThemes))
+	for name := range BundledThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseThemeColor turns a configured color into a ThemeColor. Hex values are
+// true colors, numbers below 16 are basic ANSI colors and the rest are from
+// the 256 color palette.
+func parseThemeColor(value string) (ThemeColor, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "#") {
+		if _, err := strconv.ParseUint(value[1:], 16, 32); err != nil || (len(value) != 4 && len(value) != 7) {
+			return ThemeColor{}, fmt.Errorf("invalid hex color %q", value)
+		}
+		return ThemeColor{TrueColor: value}, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > 255 {
+		return ThemeColor{}, fmt.Errorf("invalid color %q, use a hex value or a number from 0 to 255", value)
+	}
+	if n < 16 {
+		return ThemeColor{ANSI: value}, nil
+	}
+	return ThemeColor{ANSI256: value}, nil
+}
+
+// Resolve returns the configured bundled theme with the overrides applied
+func (c ThemeConfig) Resolve() (*Theme, error) {
+	name := c.Name
+	if name == "" {
+		name = DefaultTheme
+	}
+	base, ok := BundledThemes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	theme := base
+
+	switch c.ColorProfile {
+	case "", ColorProfileAuto:
+		theme.ColorProfile = ColorProfileAuto
+	case ColorProfileTrueColor, ColorProfile256, ColorProfile16:
+		theme.ColorProfile = c.ColorProfile
+	default:
+		return nil, fmt.Errorf("unknown color profile %q (available: auto, truecolor, 256, 16)", c.ColorProfile)
+	}
+
+	overrides := []struct {
+		key   string
+		value string
+		color *ThemeColor
+	}{
+		{"ahead_gaining", c.AheadGaining, &theme.AheadGaining},
+		{"ahead_losing", c.AheadLosing, &theme.AheadLosing},
+		{"behind_gaining", c.BehindGaining, &theme.BehindGaining},
+		{"behind_losing", c.BehindLosing, &theme.BehindLosing},
+		{"gold", c.Gold, &theme.Gold},
+		{"pb", c.PB, &theme.PB},
+		{"highlight", c.Highlight, &theme.Highlight},
+		{"text", c.Text, &theme.Text},
+		{"title", c.Title, &theme.Title},
+		{"muted", c.Muted, &theme.Muted},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		color, err := parseThemeColor(o.value)
+		if err != nil {
+			return nil, fmt.Errorf("theme color %s: %v", o.key, err)
+		}
+		*o.color = color
+	}
+
+	return &theme, nil
+}
+
+// LoadTheme loads the [theme] section from a TOML file
+func LoadTheme(configPath string) (*Theme, error) {
+	var config struct {
+		Theme ThemeConfig `toml:"theme"`
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return config.Theme.Resolve()
+	}
+
+	_, err := toml.DecodeFile(configPath, &config)
+	if err != nil {
+		return nil, fmt.Errorf("error loading theme config: %v", err)
+	}
+
+	theme, err := config.Theme.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("error loading theme config: %v", err)
+	}
+	return theme, nil
+}
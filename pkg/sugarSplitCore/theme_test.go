@@ -0,0 +1,46 @@
+package sugarSplitCore
+
+import "testing"
+
+func TestBundledThemesHaveDistinctColors(t *testing.T) {
+	for name, theme := range BundledThemes {
+		colors := map[string]ThemeColor{
+			"ahead_gaining":  theme.AheadGaining,
+			"ahead_losing":   theme.AheadLosing,
+			"behind_gaining": theme.BehindGaining,
+			"behind_losing":  theme.BehindLosing,
+			"gold":           theme.Gold,
+			"pb":             theme.PB,
+			"title":          theme.Title,
+			"muted":          theme.Muted,
+		}
+
+		depths := []struct {
+			name  string
+			value func(ThemeColor) string
+		}{
+			{"true color", func(c ThemeColor) string { return c.TrueColor }},
+			{"256 color", func(c ThemeColor) string { return c.ANSI256 }},
+			{"16 color", func(c ThemeColor) string { return c.ANSI }},
+		}
+		for _, depth := range depths {
+			// Gray shades run out at 16 colors
+			if name == "monochrome" && depth.name == "16 color" {
+				continue
+			}
+
+			seen := map[string]string{}
+			for key, color := range colors {
+				value := depth.value(color)
+				if value == "" {
+					t.Errorf("%s: %s has no %s value", name, key, depth.name)
+					continue
+				}
+				if other, ok := seen[value]; ok {
+					t.Errorf("%s: %s and %s are both %s at %s", name, key, other, value, depth.name)
+				}
+				seen[value] = key
+			}
+		}
+	}
+}
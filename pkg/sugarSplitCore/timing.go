@@ -0,0 +1,33 @@
+package sugarSplitCore
+
+import "time"
+
+// Timing methods stored in a splits file. The timer itself only measures real
+// time, game time is read from files made by other timers.
+const (
+	TimingRealTime = "real_time"
+	TimingGameTime = "game_time"
+)
+
+// timeFor parses the time of the given timing method
+func timeFor(realTime, gameTime, method string) time.Duration {
+	if method == TimingGameTime {
+		return ParseTime(gameTime)
+	}
+	return ParseTime(realTime)
+}
+
+// Time returns the split time for a timing method
+func (t SplitTime) Time(method string) time.Duration {
+	return timeFor(t.RealTime, t.GameTime, method)
+}
+
+// Time returns the best segment time for a timing method
+func (t BestSegmentTime) Time(method string) time.Duration {
+	return timeFor(t.RealTime, t.GameTime, method)
+}
+
+// Time returns the segment history time for a timing method
+func (t Time) Time(method string) time.Duration {
+	return timeFor(t.RealTime, t.GameTime, method)
+}
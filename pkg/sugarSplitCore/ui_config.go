@@ -3,6 +3,7 @@ package sugarSplitCore
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -10,29 +11,196 @@ import (
 type UIComponent string
 
 const (
-	UIHeader          UIComponent = "header"
-	UISplits          UIComponent = "splits"
-	UITimer           UIComponent = "timer"
-	UIPreviousSegment UIComponent = "previous_segment"
-	UIControls        UIComponent = "controls"
+	UIHeader           UIComponent = "header"
+	UISplits           UIComponent = "splits"
+	UITimer            UIComponent = "timer"
+	UIPreviousSegment  UIComponent = "previous_segment"
+	UIControls         UIComponent = "controls"
+	UIPBChance         UIComponent = "pb_chance"
+	UIGraph            UIComponent = "graph"
+	UIDetailedTimer    UIComponent = "detailed_timer"
+	UITitle            UIComponent = "title"
+	UIClock            UIComponent = "clock"
+	UISessionTimer     UIComponent = "session_timer"
+	UIText             UIComponent = "text"
+	UIBlank            UIComponent = "blank"
+	UISeparator        UIComponent = "separator"
+	UISumOfBest        UIComponent = "sum_of_best"
+	UIPossibleTimeSave UIComponent = "possible_time_save"
 )
 
+// UIComponents are all components that can be placed in the layout
+var UIComponents = []UIComponent{
+	UIHeader,
+	UISplits,
+	UITimer,
+	UIPreviousSegment,
+	UIControls,
+	UIPBChance,
+	UIGraph,
+	UIDetailedTimer,
+	UITitle,
+	UIClock,
+	UISessionTimer,
+	UIText,
+	UIBlank,
+	UISeparator,
+	UISumOfBest,
+	UIPossibleTimeSave,
+}
+
 type UISection string
 
 const (
 	SectionTop    UISection = "top"
 	SectionMiddle UISection = "middle"
 	SectionBottom UISection = "bottom"
+	// Left and right are columns beside the middle section on terminals at
+	// least WideWidth wide. On narrower ones they go above and below it.
+	SectionLeft  UISection = "left"
+	SectionRight UISection = "right"
+)
+
+var uiSections = []UISection{SectionTop, SectionMiddle, SectionBottom, SectionLeft, SectionRight}
+
+// DefaultWideWidth is the terminal width from which left and right sections
+// are shown as columns
+const DefaultWideWidth = 120
+
+// Alignments of text components
+const (
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
 )
 
+// DefaultClockFormat is the layout the clock uses, in Go's time format
+const DefaultClockFormat = "15:04:05"
+
 type UIComponentConfig struct {
 	Component UIComponent `toml:"component"`
 	Section   UISection   `toml:"section"`
+
+	// Text is shown by the text component, {variables} are filled in
+	Text string `toml:"text,omitempty"`
+	// Align is left, center or right for the text, clock and session timer
+	Align string `toml:"align,omitempty"`
+	// Lines is the height of a blank spacer
+	Lines int `toml:"lines,omitzero"`
+	// Format is the clock's time layout, see DefaultClockFormat
+	Format string `toml:"format,omitempty"`
+	// ShowPossibleTimeSave adds the possible time save to previous_segment
+	ShowPossibleTimeSave bool `toml:"show_possible_time_save,omitempty"`
+}
+
+// DefaultSection returns the section a component goes in when none is given
+func DefaultSection(component UIComponent) UISection {
+	switch component {
+	case UIHeader, UITitle:
+		return SectionTop
+	case UISplits:
+		return SectionMiddle
+	}
+	return SectionBottom
+}
+
+// DefaultGraphHeight is the height of the delta graph in lines
+const DefaultGraphHeight = 5
+
+// GraphConfig configures the delta graph component
+type GraphConfig struct {
+	Height int `toml:"height"`
+}
+
+// Fonts for the big timer
+const (
+	TimerFontAuto  = "auto"
+	TimerFontLarge = "large"
+	TimerFontSmall = "small"
+	TimerFontText  = "text"
+)
+
+// TimerConfig configures the big timer
+type TimerConfig struct {
+	// Accuracy is the number of decimal places shown, from 0 to 3
+	Accuracy int    `toml:"accuracy"`
+	Font     string `toml:"font"`
+}
+
+// DetailedTimerConfig configures what the detailed timer shows below the total time
+type DetailedTimerConfig struct {
+	ShowSegmentTimer bool `toml:"show_segment_timer"`
+	ShowComparison   bool `toml:"show_comparison"`
+	ShowGold         bool `toml:"show_gold"`
+}
+
+// Ways to draw segment and game icons
+const (
+	IconsAuto  = "auto"
+	IconsKitty = "kitty"
+	IconsSixel = "sixel"
+	IconsOff   = "off"
+)
+
+// Column types of the split list
+const (
+	ColumnDelta            = "delta"
+	ColumnSplitTime        = "split_time"
+	ColumnSegmentTime      = "segment_time"
+	ColumnSegmentDelta     = "segment_delta"
+	ColumnComparisonTime   = "comparison_time"
+	ColumnGold             = "gold"
+	ColumnPossibleTimeSave = "possible_time_save"
+)
+
+var columnTypes = []string{
+	ColumnDelta,
+	ColumnSplitTime,
+	ColumnSegmentTime,
+	ColumnSegmentDelta,
+	ColumnComparisonTime,
+	ColumnGold,
+	ColumnPossibleTimeSave,
+}
+
+// SplitColumnConfig is one time column of the split list
+type SplitColumnConfig struct {
+	Type string `toml:"type"`
+	// Comparison is the comparison the column uses, the active one if empty
+	Comparison string `toml:"comparison,omitempty"`
+	// TimingMethod is "real_time" or "game_time", real time if empty
+	TimingMethod string `toml:"timing_method,omitempty"`
+	// Width is the minimum width of the column, it grows to fit its times
+	Width int `toml:"width,omitzero"`
+}
+
+// SplitsConfig configures the split list
+type SplitsConfig struct {
+	Columns []SplitColumnConfig `toml:"columns"`
+	// Visible is the number of splits shown at once, 0 fills the free space
+	Visible int `toml:"visible"`
+	// Upcoming is the number of splits kept visible after the current one
+	Upcoming int `toml:"upcoming"`
+	// PinLast keeps the final split at the bottom of the list
+	PinLast bool `toml:"pin_last"`
 }
 
 type UIConfig struct {
-	Layout   []UIComponent       `toml:"layout"`
-	Sections []UIComponentConfig `toml:"sections"`
+	// Layout lists the components to show when no Sections are configured,
+	// each in its default section
+	Layout    []UIComponent       `toml:"layout"`
+	Sections  []UIComponentConfig `toml:"sections"`
+	WideWidth int                 `toml:"wide_width"`
+	// SplitsLayout uses the LiveSplit layout a splits file refers to
+	// instead of the configured components
+	SplitsLayout bool `toml:"splits_layout"`
+	// Icons is the graphics protocol icons are drawn with, auto picks one
+	// the terminal supports
+	Icons         string              `toml:"icons"`
+	Graph         GraphConfig         `toml:"graph"`
+	Timer         TimerConfig         `toml:"timer"`
+	DetailedTimer DetailedTimerConfig `toml:"detailed_timer"`
+	Splits        SplitsConfig        `toml:"splits"`
 }
 
 var defaultUIConfig = UIConfig{
@@ -43,15 +211,43 @@ var defaultUIConfig = UIConfig{
 		UIPreviousSegment,
 		UIControls,
 	},
+	WideWidth:    DefaultWideWidth,
+	SplitsLayout: true,
+	Icons:        IconsAuto,
+	Graph:        GraphConfig{Height: DefaultGraphHeight},
+	Timer:        TimerConfig{Accuracy: 2, Font: TimerFontAuto},
+	DetailedTimer: DetailedTimerConfig{
+		ShowSegmentTimer: true,
+		ShowComparison:   true,
+		ShowGold:         true,
+	},
+	Splits: SplitsConfig{
+		Columns: []SplitColumnConfig{
+			{Type: ColumnDelta},
+			{Type: ColumnSplitTime},
+		},
+		Upcoming: 1,
+		PinLast:  true,
+	},
+}
+
+// DefaultUIConfig returns a copy of the default UI configuration
+func DefaultUIConfig() *UIConfig {
+	config := defaultUIConfig
+	config.Layout = append([]UIComponent(nil), defaultUIConfig.Layout...)
+	config.Sections = append([]UIComponentConfig(nil), defaultUIConfig.Sections...)
+	config.Splits.Columns = append([]SplitColumnConfig(nil), defaultUIConfig.Splits.Columns...)
+	return &config
 }
 
 func LoadUIConfig(configPath string) (*UIConfig, error) {
-	var config struct {
-		UI UIConfig `toml:"ui"`
-	}
+	// Settings missing from the file keep their defaults
+	config := struct {
+		UI *UIConfig `toml:"ui"`
+	}{UI: DefaultUIConfig()}
 
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return &defaultUIConfig, nil
+		return config.UI, nil
 	}
 
 	_, err := toml.DecodeFile(configPath, &config)
@@ -61,8 +257,114 @@ func LoadUIConfig(configPath string) (*UIConfig, error) {
 
 	// If layout is empty, use default
 	if len(config.UI.Layout) == 0 {
-		return &defaultUIConfig, nil
+		config.UI.Layout = DefaultUIConfig().Layout
+	}
+
+	switch config.UI.Icons {
+	case IconsAuto, IconsKitty, IconsSixel, IconsOff:
+	default:
+		return nil, fmt.Errorf("error loading UI config: unknown icons setting %q (available: auto, kitty, sixel, off)", config.UI.Icons)
+	}
+
+	switch config.UI.Timer.Font {
+	case TimerFontAuto, TimerFontLarge, TimerFontSmall, TimerFontText:
+	default:
+		return nil, fmt.Errorf("error loading UI config: unknown timer font %q (available: auto, large, small, text)", config.UI.Timer.Font)
+	}
+
+	for _, component := range config.UI.Layout {
+		if err := validateComponent(component); err != nil {
+			return nil, fmt.Errorf("error loading UI config: layout: %v", err)
+		}
+	}
+	for i, placement := range config.UI.Sections {
+		if err := placement.validate(); err != nil {
+			return nil, fmt.Errorf("error loading UI config: [[ui.sections]] entry %d: %v", i+1, err)
+		}
+	}
+
+	for _, column := range config.UI.Splits.Columns {
+		if err := column.validate(); err != nil {
+			return nil, fmt.Errorf("error loading UI config: %v", err)
+		}
+	}
+
+	return config.UI, nil
+}
+
+// Placement returns the components to show in order with their sections.
+// Without configured sections every component of Layout is put in its
+// default section.
+func (c *UIConfig) Placement() []UIComponentConfig {
+	if len(c.Sections) > 0 {
+		placement := make([]UIComponentConfig, len(c.Sections))
+		for i, p := range c.Sections {
+			if p.Section == "" {
+				p.Section = DefaultSection(p.Component)
+			}
+			placement[i] = p
+		}
+		return placement
+	}
+
+	placement := make([]UIComponentConfig, len(c.Layout))
+	for i, component := range c.Layout {
+		placement[i] = UIComponentConfig{Component: component, Section: DefaultSection(component)}
+	}
+	return placement
+}
+
+func validateComponent(component UIComponent) error {
+	for _, c := range UIComponents {
+		if c == component {
+			return nil
+		}
+	}
+
+	names := make([]string, len(UIComponents))
+	for i, c := range UIComponents {
+		names[i] = string(c)
+	}
+	return fmt.Errorf("unknown component %q (available: %s)", component, strings.Join(names, ", "))
+}
+
+func (c UIComponentConfig) validate() error {
+	if err := validateComponent(c.Component); err != nil {
+		return err
+	}
+
+	switch c.Align {
+	case "", AlignLeft, AlignCenter, AlignRight:
+	default:
+		return fmt.Errorf("unknown align %q for component %s (available: left, center, right)", c.Align, c.Component)
 	}
 
-	return &config.UI, nil
+	if c.Section == "" {
+		return nil
+	}
+
+	names := make([]string, len(uiSections))
+	for i, section := range uiSections {
+		if section == c.Section {
+			return nil
+		}
+		names[i] = string(section)
+	}
+	return fmt.Errorf("unknown section %q for component %s (available: %s)", c.Section, c.Component, strings.Join(names, ", "))
+}
+
+func (c SplitColumnConfig) validate() error {
+	known := false
+	for _, t := range columnTypes {
+		known = known || c.Type == t
+	}
+	if !known {
+		return fmt.Errorf("unknown split column type %q (available: %s)", c.Type, strings.Join(columnTypes, ", "))
+	}
+
+	switch c.TimingMethod {
+	case "", TimingRealTime, TimingGameTime:
+		return nil
+	}
+	return fmt.Errorf("unknown timing method %q for split column %s (available: real_time, game_time)", c.TimingMethod, c.Type)
 }
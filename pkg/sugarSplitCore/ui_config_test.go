@@ -0,0 +1,32 @@
+package sugarSplitCore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadUIConfigTimerFont(t *testing.T) {
+	config, err := LoadUIConfig(writeConfig(t, "[ui.timer]\nfont = \"small\"\n"))
+	if err != nil {
+		t.Fatalf("LoadUIConfig: %v", err)
+	}
+	if config.Timer.Font != TimerFontSmall {
+		t.Errorf("font = %q, want %q", config.Timer.Font, TimerFontSmall)
+	}
+
+	_, err = LoadUIConfig(writeConfig(t, "[ui.timer]\nfont = \"huge\"\n"))
+	if err == nil || !strings.Contains(err.Error(), `unknown timer font "huge"`) {
+		t.Errorf("error = %v, want an unknown timer font error", err)
+	}
+}